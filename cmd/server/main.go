@@ -28,8 +28,31 @@ func main() {
 		log.Fatal("failed to load auth config", "error", err)
 	}
 
+	parserCfg := service.Config{}
+	if err := envconfig.Process(ctx, &parserCfg); err != nil {
+		log.Fatal("failed to load parser config", "error", err)
+	}
+
+	registry := service.DefaultExtractorRegistry()
+	if parserCfg.LexiconFile != "" {
+		data, err := os.ReadFile(parserCfg.LexiconFile)
+		if err != nil {
+			log.Fatal("failed to read lexicon file", "error", err)
+		}
+		lexicon, err := service.ParseLexicon(data)
+		if err != nil {
+			log.Fatal("failed to load lexicon", "error", err)
+		}
+		lexicons := service.DefaultLexicons()
+		lexicons[lexicon.Language] = lexicon
+		registry = service.LexiconExtractorRegistry(lexicons)
+	}
+
 	authService := auth.NewService(logger, authCfg)
-	parserService := service.NewParserService(logger)
+	parserService, err := service.NewParserService(logger, parserCfg, registry)
+	if err != nil {
+		log.Fatal("error initializing parser service", "error", err)
+	}
 	healthMetricHandler := http.NewHealthMetricParserHandler(logger, parserService)
 
 	router, err := http.NewRouter(authService, healthMetricHandler)
@@ -31,6 +31,10 @@ func (h *HealthMetricParserHandler) Parse(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
 		return
 	}
+	// the units query parameter takes precedence over the field in the body
+	if units := c.Query("units"); units != "" {
+		note.Units = units
+	}
 	valid, err := note.Valid()
 	if err != nil {
 		h.logger.Infof("error encountered: invalid clinical note: %s", err.Error())
@@ -8,6 +8,7 @@ import (
 
 	"cleo.com/internal/adapter/handler/http"
 	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
 	"cleo.com/internal/core/port/mocks"
 	"cleo.com/testsupport"
 	"github.com/brianvoe/gofakeit/v6"
@@ -18,8 +19,8 @@ import (
 func TestUserHandler_CreateUser(t *testing.T) {
 
 	testHealthMetric := model.HealthMetric{
-		Weight: "175Kg",
-		Height: "75cm",
+		Weight: &quantity.Quantity{Value: 175, Unit: "kg"},
+		Height: &quantity.Quantity{Value: 75, Unit: "cm"},
 	}
 
 	validResponseBytes, err := json.Marshal(testHealthMetric)
@@ -29,10 +30,12 @@ func TestUserHandler_CreateUser(t *testing.T) {
 		desc          string
 		parserService *mocks.HealthMetricParserServiceMock
 		clinicalNote  *model.ClinicalNote
+		query         string
 
 		expectedHttpStatus                 int
 		expectedHttpBody                   string
 		expectedParseClinicalNoteCallCount int
+		expectedUnits                      string
 	}{
 		{
 			desc:          "empty payload returns invalid request",
@@ -60,6 +63,68 @@ func TestUserHandler_CreateUser(t *testing.T) {
 			expectedHttpStatus: netHTTP.StatusBadRequest,
 			expectedHttpBody:   `{"error":"invalid request"}`,
 		},
+		{
+			desc:          "unknown bmi cut-off set returns invalid request",
+			parserService: &mocks.HealthMetricParserServiceMock{},
+			clinicalNote: &model.ClinicalNote{
+				Text:       gofakeit.Paragraph(1, 1, 1, ""),
+				BMICutOffs: "unknown",
+			},
+
+			expectedHttpStatus: netHTTP.StatusBadRequest,
+			expectedHttpBody:   `{"error":"invalid request"}`,
+		},
+		{
+			desc:          "unknown range policy returns invalid request",
+			parserService: &mocks.HealthMetricParserServiceMock{},
+			clinicalNote: &model.ClinicalNote{
+				Text:   gofakeit.Paragraph(1, 1, 1, ""),
+				Ranges: "widest",
+			},
+
+			expectedHttpStatus: netHTTP.StatusBadRequest,
+			expectedHttpBody:   `{"error":"invalid request"}`,
+		},
+		{
+			desc:          "unsupported language returns invalid request",
+			parserService: &mocks.HealthMetricParserServiceMock{},
+			clinicalNote: &model.ClinicalNote{
+				Text:     gofakeit.Paragraph(1, 1, 1, ""),
+				Language: "it-IT",
+			},
+
+			expectedHttpStatus: netHTTP.StatusBadRequest,
+			expectedHttpBody:   `{"error":"invalid request"}`,
+		},
+		{
+			desc:          "unknown units query parameter returns invalid request",
+			parserService: &mocks.HealthMetricParserServiceMock{},
+			clinicalNote: &model.ClinicalNote{
+				Text: gofakeit.Paragraph(1, 1, 1, ""),
+			},
+			query: "units=cubits",
+
+			expectedHttpStatus: netHTTP.StatusBadRequest,
+			expectedHttpBody:   `{"error":"invalid request"}`,
+		},
+		{
+			desc: "units query parameter overrides the note",
+			parserService: &mocks.HealthMetricParserServiceMock{
+				ParseClinicalNoteFunc: func(note *model.ClinicalNote) (*model.HealthMetric, error) {
+					return &testHealthMetric, nil
+				},
+			},
+			clinicalNote: &model.ClinicalNote{
+				Text:  gofakeit.Paragraph(1, 1, 1, ""),
+				Units: model.UnitSystemMetric,
+			},
+			query: "units=imperial",
+
+			expectedHttpStatus:                 netHTTP.StatusCreated,
+			expectedHttpBody:                   string(validResponseBytes),
+			expectedParseClinicalNoteCallCount: 1,
+			expectedUnits:                      model.UnitSystemImperial,
+		},
 		{
 			desc: "returns internal service error if ParseService errors",
 			parserService: &mocks.HealthMetricParserServiceMock{
@@ -96,6 +161,7 @@ func TestUserHandler_CreateUser(t *testing.T) {
 		tt := tt
 		testHandler := http.NewHealthMetricParserHandler(testsupport.Logger(), tt.parserService)
 		c, w := testsupport.NewTestContext(tt.clinicalNote)
+		c.Request.URL.RawQuery = tt.query
 
 		t.Run(tt.desc, func(t *testing.T) {
 			testHandler.Parse(c)
@@ -103,6 +169,9 @@ func TestUserHandler_CreateUser(t *testing.T) {
 			assert.JSONEq(t, tt.expectedHttpBody, w.Body.String())
 
 			require.Equal(t, tt.expectedParseClinicalNoteCallCount, len(tt.parserService.ParseClinicalNoteCalls()))
+			if tt.expectedUnits != "" {
+				assert.Equal(t, tt.expectedUnits, tt.parserService.ParseClinicalNoteCalls()[0].Note.Units)
+			}
 
 		})
 	}
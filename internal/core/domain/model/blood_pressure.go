@@ -0,0 +1,8 @@
+package model
+
+// BloodPressure is the primary blood pressure reading found in a note.
+type BloodPressure struct {
+	Systolic  float64 `json:"systolic"`
+	Diastolic float64 `json:"diastolic"`
+	Unit      string  `json:"unit"`
+}
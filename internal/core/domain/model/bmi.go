@@ -0,0 +1,21 @@
+package model
+
+// BMI is the body mass index derived from the primary weight and height, or stated in the note when
+// either of those is missing.
+type BMI struct {
+	Value    float64 `json:"value"`
+	Category string  `json:"category"`
+	CutOffs  string  `json:"cutoffs"`
+	Source   string  `json:"source"`
+	// Stated holds a BMI written in the note; Mismatch is set when it disagrees with the computed value.
+	Stated   *float64 `json:"stated,omitempty"`
+	Mismatch bool     `json:"mismatch"`
+}
+
+const (
+	BMISourceComputed = "computed"
+	BMISourceStated   = "stated"
+
+	BMICutOffsWHO        = "who"
+	BMICutOffsSouthAsian = "south_asian"
+)
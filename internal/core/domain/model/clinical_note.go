@@ -1,11 +1,49 @@
 package model
 
-import "github.com/asaskevich/govalidator"
+import (
+	"time"
+
+	"github.com/asaskevich/govalidator"
+)
 
 type ClinicalNote struct {
 	Text string `json:"text" valid:"required,stringlength(1|500)"`
+	// BMICutOffs selects the BMI category thresholds, defaulting to the WHO set.
+	BMICutOffs string `json:"bmi_cutoffs,omitempty" valid:"in(who|south_asian)"`
+	// Units selects the unit system for the primary weight, height and temperature, defaulting to metric.
+	Units string `json:"units,omitempty" valid:"in(metric|imperial|as_written)"`
+	// AgeDays selects the plausibility ranges for weight and height; without it the note is searched for an age.
+	AgeDays *int `json:"age_days,omitempty" valid:"range(0|45000)"`
+	// Ranges selects whether a weight or height written as a range is reported as its midpoint or rejected,
+	// defaulting to the midpoint.
+	Ranges string `json:"ranges,omitempty" valid:"in(midpoint|reject)"`
+	// Language selects the keywords and number format the note is read with, given as a language code or locale
+	// such as "de" or "de-AT"; without it the language is detected from the note.
+	Language string `json:"language,omitempty" valid:"matches(^(?i)(en|de|fr|es|nl)([-_][a-z]{2})?$)"`
+	// ReferenceTime is when the note was written, against which relative dates such as "yesterday" and
+	// "3 weeks ago" are resolved; without it they are left undated.
+	ReferenceTime *time.Time `json:"reference_time,omitempty"`
 }
 
+const (
+	UnitSystemMetric    = "metric"
+	UnitSystemImperial  = "imperial"
+	UnitSystemAsWritten = "as_written"
+)
+
+const (
+	RangePolicyMidpoint = "midpoint"
+	RangePolicyReject   = "reject"
+)
+
+const (
+	LanguageEnglish = "en"
+	LanguageGerman  = "de"
+	LanguageFrench  = "fr"
+	LanguageSpanish = "es"
+	LanguageDutch   = "nl"
+)
+
 func (n *ClinicalNote) Valid() (bool, error) {
 	return govalidator.ValidateStruct(n)
 }
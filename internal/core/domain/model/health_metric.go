@@ -1,6 +1,58 @@
 package model
 
+import "cleo.com/internal/core/domain/quantity"
+
+// HealthMetric holds the primary value of each metric as a number with its UCUM unit, alongside every
+// observation the parser found.
 type HealthMetric struct {
-	Weight string `json:"weight"`
-	Height string `json:"height"`
+	Weight *quantity.Quantity `json:"weight"`
+	Height *quantity.Quantity `json:"height"`
+	// WeightRange and HeightRange hold the bounds when the primary value is the midpoint of a range, and the
+	// Estimated flags mark a primary value the note qualified as approximate.
+	WeightRange     *Range `json:"weight_range,omitempty"`
+	HeightRange     *Range `json:"height_range,omitempty"`
+	WeightEstimated bool   `json:"weight_estimated,omitempty"`
+	HeightEstimated bool   `json:"height_estimated,omitempty"`
+	// BirthWeight is reported on its own and never stands in for Weight.
+	BirthWeight *quantity.Quantity `json:"birth_weight,omitempty"`
+	// HeightFeetInches repeats an imperial height as whole feet plus inches.
+	HeightFeetInches *FeetAndInches `json:"height_ft_in,omitempty"`
+	// UnitSystem is the system weight, height and temperature are reported in; observations stay metric.
+	UnitSystem string `json:"unit_system"`
+	// AgeBand is the band whose plausibility ranges were applied to weight and height.
+	AgeBand string `json:"age_band"`
+	// Language is the language the note was read in, as hinted on the request or detected.
+	Language         string             `json:"language"`
+	WeightReason     string             `json:"weight_reason,omitempty"`
+	HeightReason     string             `json:"height_reason,omitempty"`
+	WeightStatus     string             `json:"weight_status,omitempty"`
+	HeightStatus     string             `json:"height_status,omitempty"`
+	WeightChange     *WeightChange      `json:"weight_change,omitempty"`
+	BMI              *BMI               `json:"bmi,omitempty"`
+	BloodPressure    *BloodPressure     `json:"blood_pressure,omitempty"`
+	HeartRate        *quantity.Quantity `json:"heart_rate,omitempty"`
+	RespiratoryRate  *quantity.Quantity `json:"respiratory_rate,omitempty"`
+	OxygenSaturation *quantity.Quantity `json:"oxygen_saturation,omitempty"`
+	// OxygenSupport says whether the saturation was measured on room air or supplemental oxygen, with the
+	// flow in OxygenFlow when stated.
+	OxygenSupport string             `json:"oxygen_support,omitempty"`
+	OxygenFlow    *quantity.Quantity `json:"oxygen_flow,omitempty"`
+	Temperature   *quantity.Quantity `json:"temperature,omitempty"`
+	// Labs holds one result per analyte found, in the order the analytes are listed in the model.
+	Labs []LabResult `json:"labs,omitempty"`
+	// Medications lists every dose found, in order of appearance.
+	Medications []Medication `json:"medications,omitempty"`
+	// NeedsReview lists the observations below the confidence threshold, which are not reported as facts.
+	NeedsReview  []Observation `json:"needs_review,omitempty"`
+	Observations []Observation `json:"observations,omitempty"`
+}
+
+type FeetAndInches struct {
+	Feet   int     `json:"feet"`
+	Inches float64 `json:"inches"`
 }
+
+const (
+	OxygenRoomAir      = "room_air"
+	OxygenSupplemental = "supplemental"
+)
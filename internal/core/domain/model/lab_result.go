@@ -0,0 +1,15 @@
+package model
+
+import "cleo.com/internal/core/domain/quantity"
+
+// LabResult is the primary laboratory value found for one analyte, as written and in both SI and conventional
+// units. Analytes with a single unit, such as eGFR, report it as both.
+type LabResult struct {
+	Analyte      string            `json:"analyte"`
+	Value        float64           `json:"value"`
+	Unit         string            `json:"unit"`
+	SI           quantity.Quantity `json:"si"`
+	Conventional quantity.Quantity `json:"conventional"`
+	// SpecimenDate is the date the sample was taken, as YYYY-MM-DD.
+	SpecimenDate string `json:"specimen_date,omitempty"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "cleo.com/internal/core/domain/quantity"
+
+// Medication is a dose found in a note, as written. A per-kg dose is also given as an absolute dose for the
+// primary weight, per dose or, for a dose per kg per day, as a daily total.
+type Medication struct {
+	Drug      string  `json:"drug"`
+	Dose      float64 `json:"dose"`
+	Unit      string  `json:"unit"`
+	Route     string  `json:"route,omitempty"`
+	Frequency string  `json:"frequency,omitempty"`
+	// CalculatedDose is a per-kg dose multiplied by the primary weight. DailyDose is set instead for a dose per kg
+	// per day, which is divided between doses.
+	CalculatedDose *quantity.Quantity `json:"calculated_dose,omitempty"`
+	DailyDose      *quantity.Quantity `json:"daily_dose,omitempty"`
+	// StatedDose is an absolute dose written beside a per-kg dose; DoseMismatch is set when it differs from
+	// CalculatedDose, or DailyDose for a dose per day, by more than the configured tolerance.
+	StatedDose   *quantity.Quantity `json:"stated_dose,omitempty"`
+	DoseMismatch bool               `json:"dose_mismatch,omitempty"`
+}
+
+// Routes of administration.
+const (
+	RouteOral          = "oral"
+	RouteIntravenous   = "intravenous"
+	RouteIntramuscular = "intramuscular"
+	RouteSubcutaneous  = "subcutaneous"
+	RouteRectal        = "rectal"
+	RouteSublingual    = "sublingual"
+	RouteInhaled       = "inhaled"
+	RouteTopical       = "topical"
+)
+
+// Dose frequencies; a dose every few hours is reported as "every_<n>_hours".
+const (
+	FrequencyOnce            = "once"
+	FrequencyOnceDaily       = "once_daily"
+	FrequencyTwiceDaily      = "twice_daily"
+	FrequencyThreeTimesDaily = "three_times_daily"
+	FrequencyFourTimesDaily  = "four_times_daily"
+	FrequencyInTheMorning    = "in_the_morning"
+	FrequencyAtNight         = "at_night"
+	FrequencyAsNeeded        = "as_needed"
+)
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"time"
+
+	"cleo.com/internal/core/domain/quantity"
+)
+
+// Observation is a single metric mention found in a clinical note. Offsets refer to the
+// original note text so that a reviewer can highlight every candidate value.
+type Observation struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+	// Value is normalized to the canonical unit for the kind; Unit is its UCUM code.
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+	// Written is the value in the unit used in the note, before normalization.
+	Written   *quantity.Quantity `json:"written,omitempty"`
+	Start     int                `json:"start"`
+	End       int                `json:"end"`
+	RuneStart int                `json:"rune_start"`
+	RuneEnd   int                `json:"rune_end"`
+	// Score ranks competing observations of the same kind; Cues lists the context words behind it.
+	Score int      `json:"score"`
+	Cues  []string `json:"cues,omitempty"`
+	// Negated, Historical and Uncertain are set from the trigger phrases around the mention. Negated and
+	// historical observations are never reported as the primary value.
+	Negated    bool `json:"negated"`
+	Historical bool `json:"historical"`
+	Uncertain  bool `json:"uncertain"`
+	// Range holds the bounds of a value written as a range, in the unit used in the note like Written; Estimated
+	// is set for a value qualified as approximate, e.g. "approx 80kg" or "~6ft".
+	Range     *Range `json:"range,omitempty"`
+	Estimated bool   `json:"estimated"`
+	// Confidence, from 0 to 1, weighs the keyword, unit, context, section, qualifiers and plausibility of the
+	// mention. NeedsReview is set below the configured threshold and keeps the observation from being reported
+	// as a primary value.
+	Confidence  float64 `json:"confidence"`
+	NeedsReview bool    `json:"needs_review"`
+	// Implausible is set on a value outside its plausible range, for weight and height that of the age band, and
+	// holds it back for review.
+	Implausible bool `json:"implausible"`
+	// Section is the note section the mention appears under, or empty before the first heading.
+	Section string `json:"section,omitempty"`
+	// Direction and Period are only set on weight change observations, whose value is the magnitude of the change.
+	Direction string `json:"direction,omitempty"`
+	Period    string `json:"period,omitempty"`
+	// Drug, Route and Frequency are only set on medication dose observations.
+	Drug      string `json:"drug,omitempty"`
+	Route     string `json:"route,omitempty"`
+	Frequency string `json:"frequency,omitempty"`
+	// EffectiveTime is when the measurement was taken, from a date or relative phrase in the note such as
+	// "12/03/2025" or "yesterday".
+	EffectiveTime *time.Time `json:"effective_time,omitempty"`
+	// Subject is who the measurement belongs to; only the patient's observations are reported as primary values.
+	Subject string `json:"subject"`
+}
+
+func (o Observation) Quantity() quantity.Quantity {
+	return quantity.Quantity{Value: o.Value, Unit: o.Unit}
+}
+
+const (
+	KindWeight = "weight"
+	// KindBirthWeight is kept apart from KindWeight so that a birth weight is never reported as the current weight.
+	KindBirthWeight = "birth_weight"
+	KindHeight      = "height"
+	// KindWeightChange is a loss or gain in weight; it never populates the absolute weight.
+	KindWeightChange = "weight_change"
+	KindBMI          = "bmi"
+	// A blood pressure reading is reported as a systolic and a diastolic observation sharing one span.
+	KindSystolicBP  = "systolic_bp"
+	KindDiastolicBP = "diastolic_bp"
+
+	KindHeartRate        = "heart_rate"
+	KindRespiratoryRate  = "respiratory_rate"
+	KindOxygenSaturation = "oxygen_saturation"
+	// KindOxygenFlow shares the span of an SpO2 reading; a value of 0 L/min means room air.
+	KindOxygenFlow  = "oxygen_flow"
+	KindTemperature = "temperature"
+
+	// KindMedicationDose is a dose as prescribed, absolute or per kg of body weight. KindStatedDose is an absolute
+	// dose written beside a per-kg dose, sharing its span.
+	KindMedicationDose = "medication_dose"
+	KindStatedDose     = "stated_dose"
+
+	// Laboratory analytes; each observation is in the SI unit of its analyte.
+	KindHbA1c            = "hba1c"
+	KindGlucose          = "glucose"
+	KindTotalCholesterol = "total_cholesterol"
+	KindLDLCholesterol   = "ldl_cholesterol"
+	KindCreatinine       = "creatinine"
+	KindEGFR             = "egfr"
+)
+
+// Sections of a clinical note, from SOAP and structured headings.
+const (
+	SectionVitals             = "vitals"
+	SectionObjective          = "objective"
+	SectionExamination        = "examination"
+	SectionSubjective         = "subjective"
+	SectionAssessment         = "assessment"
+	SectionPastMedicalHistory = "past_medical_history"
+	SectionFamilyHistory      = "family_history"
+	SectionSocialHistory      = "social_history"
+	SectionPlan               = "plan"
+)
+
+// Measurement statuses explain why no primary value was reported for a metric.
+const (
+	StatusDeclined     = "declined"
+	StatusUnobtainable = "unobtainable"
+	StatusNotRecorded  = "not_recorded"
+	// StatusRangeRejected is reported when the only values were ranges and the request rejects ranges.
+	StatusRangeRejected = "range_rejected"
+	// StatusNeedsReview is reported when every mention fell below the confidence threshold.
+	StatusNeedsReview = "needs_review"
+)
+
+// Subjects a measurement in the note may belong to.
+const (
+	SubjectPatient = "patient"
+	SubjectMother  = "mother"
+	SubjectFather  = "father"
+	SubjectSibling = "sibling"
+	SubjectFetus   = "fetus"
+	SubjectDonor   = "donor"
+)
@@ -0,0 +1,9 @@
+package model
+
+import "cleo.com/internal/core/domain/quantity"
+
+// Range is a value written as bounds, e.g. "70-72 kg". An observation of a range takes the midpoint as its value.
+type Range struct {
+	Low  quantity.Quantity `json:"low"`
+	High quantity.Quantity `json:"high"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "cleo.com/internal/core/domain/quantity"
+
+// WeightChange is the primary change in weight described in a note, e.g. "lost 5 kg in 3 months". Change is
+// always a positive magnitude, absolute or in %, and Direction says which way it went.
+type WeightChange struct {
+	Direction string            `json:"direction"`
+	Change    quantity.Quantity `json:"change"`
+	// Period is the stated time frame as written, e.g. "3 months" or "since June".
+	Period string `json:"period,omitempty"`
+}
+
+const (
+	WeightLoss = "loss"
+	WeightGain = "gain"
+)
@@ -0,0 +1,98 @@
+package quantity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrIncompatibleUnits = errors.New("incompatible units")
+
+// Quantity is a numeric value with its UCUM unit code.
+type Quantity struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// New returns a quantity, rejecting unit codes that are not known.
+func New(value float64, code string) (Quantity, error) {
+	if _, err := Lookup(code); err != nil {
+		return Quantity{}, err
+	}
+
+	return Quantity{Value: value, Unit: code}, nil
+}
+
+// To converts the quantity into another unit of the same dimension.
+func (q Quantity) To(code string) (Quantity, error) {
+	from, err := Lookup(q.Unit)
+	if err != nil {
+		return Quantity{}, err
+	}
+	to, err := Lookup(code)
+	if err != nil {
+		return Quantity{}, err
+	}
+	if from.Dimension != to.Dimension {
+		return Quantity{}, fmt.Errorf("%w: %s to %s", ErrIncompatibleUnits, from.Code, to.Code)
+	}
+	if from.Code == to.Code {
+		return q, nil
+	}
+	base := q.Value*from.factor + from.offset
+
+	return Quantity{Value: (base - to.offset) / to.factor, Unit: to.Code}, nil
+}
+
+// ToAnalyte converts the quantity into another unit like To, and also between mass and substance concentrations of
+// an analyte with the given molar mass in g/mol, e.g. glucose at 180.16 g/mol from mg/dL to mmol/L.
+func (q Quantity) ToAnalyte(code string, molarMass float64) (Quantity, error) {
+	from, err := Lookup(q.Unit)
+	if err != nil {
+		return Quantity{}, err
+	}
+	to, err := Lookup(code)
+	if err != nil {
+		return Quantity{}, err
+	}
+	// base units are kg/L and mol/L, so a molar mass in g/mol is divided by 1000 to give kg/mol
+	base := q.Value * from.factor
+	switch {
+	case from.Dimension == MassConcentration && to.Dimension == SubstanceConcentration:
+		base /= molarMass / 1000
+	case from.Dimension == SubstanceConcentration && to.Dimension == MassConcentration:
+		base *= molarMass / 1000
+	default:
+		return q.To(code)
+	}
+
+	return Quantity{Value: base / to.factor, Unit: to.Code}, nil
+}
+
+// Add returns the sum in the unit of q, e.g. 5 [ft_i] plus 9 [in_i]. Temperatures cannot be added.
+func (q Quantity) Add(other Quantity) (Quantity, error) {
+	unit, err := Lookup(q.Unit)
+	if err != nil {
+		return Quantity{}, err
+	}
+	if unit.Dimension == Temperature {
+		return Quantity{}, fmt.Errorf("%w: cannot add temperatures", ErrIncompatibleUnits)
+	}
+	converted, err := other.To(q.Unit)
+	if err != nil {
+		return Quantity{}, err
+	}
+
+	return Quantity{Value: q.Value + converted.Value, Unit: q.Unit}, nil
+}
+
+// Round returns the quantity with its value rounded to the given number of decimal places.
+func (q Quantity) Round(precision int) Quantity {
+	p := math.Pow(10, float64(precision))
+
+	return Quantity{Value: math.Round(q.Value*p) / p, Unit: q.Unit}
+}
+
+func (q Quantity) String() string {
+	return fmt.Sprintf("%g %s", q.Value, q.Unit)
+}
@@ -0,0 +1,216 @@
+package quantity_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantity_To(t *testing.T) {
+	tests := []struct {
+		desc          string
+		quantity      quantity.Quantity
+		unit          string
+		expected      quantity.Quantity
+		expectedError string
+	}{
+		{
+			desc:     "pounds to kilograms",
+			quantity: quantity.Quantity{Value: 100, Unit: "[lb_av]"},
+			unit:     "kg",
+			expected: quantity.Quantity{Value: 45.359237, Unit: "kg"},
+		},
+		{
+			desc:     "stones to pounds",
+			quantity: quantity.Quantity{Value: 12, Unit: "[stone_av]"},
+			unit:     "[lb_av]",
+			expected: quantity.Quantity{Value: 168, Unit: "[lb_av]"},
+		},
+		{
+			desc:     "grams to kilograms",
+			quantity: quantity.Quantity{Value: 3450, Unit: "g"},
+			unit:     "kg",
+			expected: quantity.Quantity{Value: 3.45, Unit: "kg"},
+		},
+		{
+			desc:     "feet to centimetres",
+			quantity: quantity.Quantity{Value: 6, Unit: "[ft_i]"},
+			unit:     "cm",
+			expected: quantity.Quantity{Value: 182.88, Unit: "cm"},
+		},
+		{
+			desc:     "centimetres to inches",
+			quantity: quantity.Quantity{Value: 254, Unit: "cm"},
+			unit:     "[in_i]",
+			expected: quantity.Quantity{Value: 100, Unit: "[in_i]"},
+		},
+		{
+			desc:     "fahrenheit to celsius",
+			quantity: quantity.Quantity{Value: 212, Unit: "[degF]"},
+			unit:     "Cel",
+			expected: quantity.Quantity{Value: 100, Unit: "Cel"},
+		},
+		{
+			desc:     "celsius to fahrenheit",
+			quantity: quantity.Quantity{Value: 37, Unit: "Cel"},
+			unit:     "[degF]",
+			expected: quantity.Quantity{Value: 98.6, Unit: "[degF]"},
+		},
+		{
+			desc:     "mass concentrations",
+			quantity: quantity.Quantity{Value: 1, Unit: "g/L"},
+			unit:     "mg/dL",
+			expected: quantity.Quantity{Value: 100, Unit: "mg/dL"},
+		},
+		{
+			desc:          "different dimensions are rejected",
+			quantity:      quantity.Quantity{Value: 75, Unit: "kg"},
+			unit:          "cm",
+			expectedError: "incompatible units: kg to cm",
+		},
+		{
+			desc:          "substance and mass concentrations need an analyte to convert",
+			quantity:      quantity.Quantity{Value: 5.5, Unit: "mmol/L"},
+			unit:          "mg/dL",
+			expectedError: "incompatible units: mmol/L to mg/dL",
+		},
+		{
+			desc:          "unknown target unit is rejected",
+			quantity:      quantity.Quantity{Value: 75, Unit: "kg"},
+			unit:          "furlong",
+			expectedError: "unknown unit: furlong",
+		},
+		{
+			desc:          "unknown source unit is rejected",
+			quantity:      quantity.Quantity{Value: 75, Unit: "kilos"},
+			unit:          "kg",
+			expectedError: "unknown unit: kilos",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			converted, err := tt.quantity.To(tt.unit)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.Unit, converted.Unit)
+			assert.InDelta(t, tt.expected.Value, converted.Value, 1e-9)
+		})
+	}
+}
+
+func TestQuantity_ToAnalyte(t *testing.T) {
+	tests := []struct {
+		desc          string
+		quantity      quantity.Quantity
+		unit          string
+		molarMass     float64
+		expected      quantity.Quantity
+		expectedError string
+	}{
+		{
+			desc:      "mass to substance concentration",
+			quantity:  quantity.Quantity{Value: 180.156, Unit: "mg/dL"},
+			unit:      "mmol/L",
+			molarMass: 180.156,
+			expected:  quantity.Quantity{Value: 10, Unit: "mmol/L"},
+		},
+		{
+			desc:      "substance to mass concentration",
+			quantity:  quantity.Quantity{Value: 88.4, Unit: "umol/L"},
+			unit:      "mg/dL",
+			molarMass: 113.12,
+			expected:  quantity.Quantity{Value: 1, Unit: "mg/dL"},
+		},
+		{
+			desc:      "same dimension ignores the molar mass",
+			quantity:  quantity.Quantity{Value: 1, Unit: "g/L"},
+			unit:      "mg/dL",
+			molarMass: 180.156,
+			expected:  quantity.Quantity{Value: 100, Unit: "mg/dL"},
+		},
+		{
+			desc:          "other dimensions are rejected",
+			quantity:      quantity.Quantity{Value: 48, Unit: "mmol/mol"},
+			unit:          "mmol/L",
+			molarMass:     180.156,
+			expectedError: "incompatible units: mmol/mol to mmol/L",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			converted, err := tt.quantity.ToAnalyte(tt.unit, tt.molarMass)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.Unit, converted.Unit)
+			assert.InDelta(t, tt.expected.Value, converted.Value, 1e-3)
+		})
+	}
+}
+
+func TestQuantity_Add(t *testing.T) {
+	height, err := quantity.Quantity{Value: 5, Unit: "[ft_i]"}.Add(quantity.Quantity{Value: 9, Unit: "[in_i]"})
+	require.NoError(t, err)
+	assert.Equal(t, "[ft_i]", height.Unit)
+	assert.InDelta(t, 5.75, height.Value, 1e-9)
+
+	_, err = quantity.Quantity{Value: 37, Unit: "Cel"}.Add(quantity.Quantity{Value: 1, Unit: "Cel"})
+	assert.EqualError(t, err, "incompatible units: cannot add temperatures")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spelling      string
+		expectedCode  string
+		expectedError string
+	}{
+		{spelling: "Kilograms", expectedCode: "kg"},
+		{spelling: "LBS", expectedCode: "[lb_av]"},
+		{spelling: "stone", expectedCode: "[stone_av]"},
+		{spelling: "″", expectedCode: "[in_i]"},
+		{spelling: "° F", expectedCode: "[degF]"},
+		{spelling: "degrees celsius", expectedCode: "Cel"},
+		{spelling: "mmHg", expectedCode: "mm[Hg]"},
+		{spelling: "mcg", expectedCode: "ug"},
+		{spelling: "mg per kg", expectedCode: "mg/kg"},
+		{spelling: "mL/min/1.73 m²", expectedCode: "mL/min/{1.73_m2}"},
+		{spelling: "furlongs", expectedError: "unknown unit: furlongs"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.spelling, func(t *testing.T) {
+			unit, err := quantity.Parse(tt.spelling)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, unit.Code)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	q, err := quantity.New(75, "kg")
+	require.NoError(t, err)
+	assert.Equal(t, quantity.Quantity{Value: 75, Unit: "kg"}, q)
+
+	_, err = quantity.New(75, "kgs")
+	assert.ErrorIs(t, err, quantity.ErrUnknownUnit)
+}
@@ -0,0 +1,138 @@
+package quantity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Dimension is the physical kind of a quantity. Only units of the same dimension convert into one another.
+type Dimension string
+
+const (
+	Mass                   Dimension = "mass"
+	Length                 Dimension = "length"
+	Temperature            Dimension = "temperature"
+	MassConcentration      Dimension = "mass_concentration"
+	SubstanceConcentration Dimension = "substance_concentration"
+	SubstanceFraction      Dimension = "substance_fraction"
+	Pressure               Dimension = "pressure"
+	Frequency              Dimension = "frequency"
+	Fraction               Dimension = "fraction"
+	VolumeFlow             Dimension = "volume_flow"
+	MassPerArea            Dimension = "mass_per_area"
+	// MassFraction is a mass per kilogram of body weight, as weight-based doses are written.
+	MassFraction Dimension = "mass_fraction"
+	// FiltrationRate is a volume flow per standard body surface area of 1.73 m², as eGFR is reported.
+	FiltrationRate Dimension = "filtration_rate"
+)
+
+// Unit is a UCUM unit. Values convert to the base unit of their dimension as value*factor + offset; every
+// factor below is an exact definition, not a rounded approximation.
+type Unit struct {
+	Code      string
+	Dimension Dimension
+	factor    float64
+	offset    float64
+}
+
+var ErrUnknownUnit = errors.New("unknown unit")
+
+// units is keyed by UCUM code. Base units: kg, m, K, kg/L, mol/L, 1, Pa, /s, L/s, kg/m2, mL/min/{1.73_m2} and
+// kg/kg.
+var units = map[string]Unit{
+	"kg":         {Code: "kg", Dimension: Mass, factor: 1},
+	"g":          {Code: "g", Dimension: Mass, factor: 1e-3},
+	"mg":         {Code: "mg", Dimension: Mass, factor: 1e-6},
+	"ug":         {Code: "ug", Dimension: Mass, factor: 1e-9},
+	"[lb_av]":    {Code: "[lb_av]", Dimension: Mass, factor: 0.45359237},
+	"[oz_av]":    {Code: "[oz_av]", Dimension: Mass, factor: 0.45359237 / 16},
+	"[stone_av]": {Code: "[stone_av]", Dimension: Mass, factor: 0.45359237 * 14},
+
+	"m":      {Code: "m", Dimension: Length, factor: 1},
+	"cm":     {Code: "cm", Dimension: Length, factor: 1e-2},
+	"mm":     {Code: "mm", Dimension: Length, factor: 1e-3},
+	"[in_i]": {Code: "[in_i]", Dimension: Length, factor: 0.0254},
+	"[ft_i]": {Code: "[ft_i]", Dimension: Length, factor: 0.3048},
+
+	"K":      {Code: "K", Dimension: Temperature, factor: 1},
+	"Cel":    {Code: "Cel", Dimension: Temperature, factor: 1, offset: 273.15},
+	"[degF]": {Code: "[degF]", Dimension: Temperature, factor: 5.0 / 9, offset: 273.15 - 32*5.0/9},
+
+	"g/L":   {Code: "g/L", Dimension: MassConcentration, factor: 1e-3},
+	"mg/dL": {Code: "mg/dL", Dimension: MassConcentration, factor: 1e-5},
+
+	"mol/L":    {Code: "mol/L", Dimension: SubstanceConcentration, factor: 1},
+	"mmol/L":   {Code: "mmol/L", Dimension: SubstanceConcentration, factor: 1e-3},
+	"umol/L":   {Code: "umol/L", Dimension: SubstanceConcentration, factor: 1e-6},
+	"mmol/mol": {Code: "mmol/mol", Dimension: SubstanceFraction, factor: 1e-3},
+
+	"mm[Hg]": {Code: "mm[Hg]", Dimension: Pressure, factor: 133.322387415},
+	"/min":   {Code: "/min", Dimension: Frequency, factor: 1.0 / 60},
+	"%":      {Code: "%", Dimension: Fraction, factor: 1e-2},
+	"L/min":  {Code: "L/min", Dimension: VolumeFlow, factor: 1.0 / 60},
+	"kg/m2":  {Code: "kg/m2", Dimension: MassPerArea, factor: 1},
+
+	"mL/min/{1.73_m2}": {Code: "mL/min/{1.73_m2}", Dimension: FiltrationRate, factor: 1},
+
+	"g/kg":  {Code: "g/kg", Dimension: MassFraction, factor: 1e-3},
+	"mg/kg": {Code: "mg/kg", Dimension: MassFraction, factor: 1e-6},
+	"ug/kg": {Code: "ug/kg", Dimension: MassFraction, factor: 1e-9},
+}
+
+// spellings maps the ways a unit is written in clinical notes, lower-cased with spaces removed, to UCUM codes.
+var spellings = map[string]string{
+	"kg": "kg", "kgs": "kg", "kilo": "kg", "kilos": "kg", "kilogram": "kg", "kilograms": "kg",
+	"g": "g", "gm": "g", "gms": "g", "gram": "g", "grams": "g", "gramme": "g", "grammes": "g",
+	"lb": "[lb_av]", "lbs": "[lb_av]", "pound": "[lb_av]", "pounds": "[lb_av]",
+	"oz": "[oz_av]", "ounce": "[oz_av]", "ounces": "[oz_av]",
+	"st": "[stone_av]", "stone": "[stone_av]", "stones": "[stone_av]",
+	"mg": "mg", "milligram": "mg", "milligrams": "mg", "milligramme": "mg", "milligrammes": "mg",
+	"ug": "ug", "mcg": "ug", "µg": "ug", "μg": "ug", "microgram": "ug", "micrograms": "ug",
+
+	"m": "m", "metre": "m", "metres": "m", "meter": "m", "meters": "m",
+	"cm": "cm", "cms": "cm", "centimetre": "cm", "centimetres": "cm", "centimeter": "cm", "centimeters": "cm",
+	"mm": "mm", "millimetre": "mm", "millimetres": "mm", "millimeter": "mm", "millimeters": "mm",
+	"in": "[in_i]", "ins": "[in_i]", "inch": "[in_i]", "inches": "[in_i]", `"`: "[in_i]", "″": "[in_i]", "”": "[in_i]", "''": "[in_i]",
+	"ft": "[ft_i]", "feet": "[ft_i]", "foot": "[ft_i]", "'": "[ft_i]", "′": "[ft_i]", "’": "[ft_i]",
+
+	"c": "Cel", "°c": "Cel", "ºc": "Cel", "celsius": "Cel", "centigrade": "Cel",
+	"degc": "Cel", "degreec": "Cel", "degreesc": "Cel", "degreecelsius": "Cel", "degreescelsius": "Cel",
+	"degreecentigrade": "Cel", "degreescentigrade": "Cel",
+	"f": "[degF]", "°f": "[degF]", "ºf": "[degF]", "fahrenheit": "[degF]",
+	"degf": "[degF]", "degreef": "[degF]", "degreesf": "[degF]", "degreefahrenheit": "[degF]", "degreesfahrenheit": "[degF]",
+
+	"g/l": "g/L", "mg/dl": "mg/dL",
+	"mmol/l": "mmol/L", "umol/l": "umol/L", "µmol/l": "umol/L", "μmol/l": "umol/L", "micromol/l": "umol/L", "mmol/mol": "mmol/mol",
+
+	"mmhg": "mm[Hg]",
+	"bpm":  "/min", "/min": "/min", "perminute": "/min", "beatsperminute": "/min", "beats/min": "/min",
+	"breathsperminute": "/min", "breaths/min": "/min",
+	"%": "%", "percent": "%",
+	"l/min": "L/min", "lpm": "L/min",
+	"kg/m2": "kg/m2", "kg/m²": "kg/m2",
+	"g/kg": "g/kg", "gperkg": "g/kg", "mg/kg": "mg/kg", "mgperkg": "mg/kg",
+	"ug/kg": "ug/kg", "mcg/kg": "ug/kg", "µg/kg": "ug/kg", "μg/kg": "ug/kg", "ugperkg": "ug/kg", "mcgperkg": "ug/kg",
+	"ml/min/1.73m2": "mL/min/{1.73_m2}", "ml/min/1.73m²": "mL/min/{1.73_m2}", "ml/min/{1.73_m2}": "mL/min/{1.73_m2}",
+}
+
+// Lookup returns the unit for a UCUM code.
+func Lookup(code string) (Unit, error) {
+	unit, ok := units[code]
+	if !ok {
+		return Unit{}, fmt.Errorf("%w: %s", ErrUnknownUnit, code)
+	}
+
+	return unit, nil
+}
+
+// Parse returns the unit for a spelling found in a clinical note, e.g. "kgs", "Pounds" or "°F".
+func Parse(spelling string) (Unit, error) {
+	key := strings.ToLower(strings.Join(strings.Fields(spelling), ""))
+	code, ok := spellings[key]
+	if !ok {
+		return Unit{}, fmt.Errorf("%w: %s", ErrUnknownUnit, spelling)
+	}
+
+	return Lookup(code)
+}
@@ -0,0 +1,22 @@
+package port
+
+import "cleo.com/internal/core/domain/model"
+
+//go:generate moq -pkg mocks -out ./mocks/metric_extractor.go . MetricExtractor
+
+// MetricExtractor finds every mention of one family of metrics in a clinical note.
+type MetricExtractor interface {
+	// Name identifies the extractor in configuration, e.g. "weight" or "blood_pressure".
+	Name() string
+	// Extract is given the note with numbers in plain decimal-point form and, in English notes only, spelled-out
+	// numbers written as digits. Observation offsets refer to that text and are moved back onto the original note
+	// by the parser.
+	Extract(text string) ([]model.Observation, error)
+}
+
+// LocalizedMetricExtractor is a MetricExtractor whose vocabulary depends on the language of the note. The parser
+// calls ExtractLanguage in place of Extract, with the language hinted on the request or detected.
+type LocalizedMetricExtractor interface {
+	MetricExtractor
+	ExtractLanguage(text, language string) ([]model.Observation, error)
+}
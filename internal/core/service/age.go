@@ -0,0 +1,206 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+var (
+	// ageOldRegex matches "3 day old", "6-week-old", "2 months old" and "45 yrs old".
+	ageOldRegex = regexp.MustCompile(`(?i)\b(\d{1,3})\s*-?\s*(days?|weeks?|wks?|months?|mths?|years?|yrs?)\s*-?\s*old\b`)
+	// agedRegex matches "aged 45", "age: 3 months", "45yo" and "45 y/o".
+	agedRegex = regexp.MustCompile(`(?i)(?:\bage(?:d)?\s*:?\s*(\d{1,3})(?:\s*(days?|weeks?|wks?|months?|mths?|years?|yrs?))?\b|\b(\d{1,3})\s*(?:yo|y/o|y\.o\.)(?:\s|$|[,.;]))`)
+	// ageFractionRegex matches the UK shorthand of days/7, weeks/52 and months/12, e.g. "2/12 old".
+	ageFractionRegex = regexp.MustCompile(`(?i)\b(\d{1,2})/(7|52|12)\s*(?:old|baby|infant|boy|girl)\b`)
+)
+
+// ageBand holds the plausible weight and height for patients younger than maxDays.
+type ageBand struct {
+	name     string
+	maxDays  float64
+	weightKg [2]float64
+	heightCm [2]float64
+}
+
+var (
+	ageBands = []ageBand{
+		{name: "neonate", maxDays: 28, weightKg: [2]float64{0.3, 7}, heightCm: [2]float64{20, 65}},
+		{name: "infant", maxDays: 365, weightKg: [2]float64{1.5, 15}, heightCm: [2]float64{40, 95}},
+		{name: "child", maxDays: 12 * 365, weightKg: [2]float64{5, 120}, heightCm: [2]float64{60, 190}},
+		{name: "adolescent", maxDays: 18 * 365, weightKg: [2]float64{15, 250}, heightCm: [2]float64{100, 230}},
+		{name: "adult", maxDays: math.Inf(1), weightKg: [2]float64{20, maxWeightKg}, heightCm: [2]float64{100, maxHeightCm}},
+	}
+	// unknownAgeBand keeps the historical fixed windows for notes that give no age.
+	unknownAgeBand = ageBand{name: "unknown", weightKg: [2]float64{minWeightKg, maxWeightKg}, heightCm: [2]float64{minHeightCm, maxHeightCm}}
+	// anyAgeBand spans the ranges of every band, for values that no age could explain.
+	anyAgeBand = ageBand{name: "any", weightKg: [2]float64{0.3, maxWeightKg}, heightCm: [2]float64{minHeightCm, maxHeightCm}}
+)
+
+// resolveAgeBand picks the band for the age given on the request, or failing that the first age phrase in
+// the note that describes the patient, so that "mother aged 30" does not set the band of her baby.
+func resolveAgeBand(ageDays *int, original string, normalized normalizedText, observations []model.Observation) ageBand {
+	if ageDays != nil {
+		return ageBandFor(float64(*ageDays))
+	}
+	spans := observationSpans(observations)
+	isPatient := func(start, end int) bool {
+		start, end = normalized.originalSpan(start, end)
+		return subjectOf(original, start, end, spans) == model.SubjectPatient
+	}
+	if days, ok := ageInDays(normalized.text, isPatient); ok {
+		return ageBandFor(days)
+	}
+
+	return unknownAgeBand
+}
+
+func ageBandFor(days float64) ageBand {
+	for _, band := range ageBands {
+		if days < band.maxDays {
+			return band
+		}
+	}
+
+	return ageBands[len(ageBands)-1]
+}
+
+// ageInDays reads the first age phrase in the note whose span the filter accepts.
+func ageInDays(text string, accept func(start, end int) bool) (float64, bool) {
+	if m := firstAccepted(ageOldRegex, text, accept); m != nil {
+		return daysFor(m[1], m[2])
+	}
+	if m := firstAccepted(ageFractionRegex, text, accept); m != nil {
+		return daysFor(m[1], map[string]string{"7": "days", "52": "weeks", "12": "months"}[m[2]])
+	}
+	if m := firstAccepted(agedRegex, text, accept); m != nil {
+		if m[1] != "" {
+			return daysFor(m[1], m[2])
+		}
+		return daysFor(m[3], "years")
+	}
+
+	return 0, false
+}
+
+// firstAccepted returns the submatches of the first match of the pattern whose span the filter accepts.
+func firstAccepted(pattern *regexp.Regexp, text string, accept func(start, end int) bool) []string {
+	for _, m := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		if accept(m[0], m[1]) {
+			return submatches(text, m)
+		}
+	}
+
+	return nil
+}
+
+// daysFor converts an age value and unit to days; a bare value is taken as years.
+func daysFor(valStr, unit string) (float64, bool) {
+	v, err := strconv.ParseFloat(valStr, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch u := strings.ToLower(unit); {
+	case strings.HasPrefix(u, "d"):
+		return v, true
+	case strings.HasPrefix(u, "w"):
+		return v * 7, true
+	case strings.HasPrefix(u, "m"):
+		return v * 365.25 / 12, true
+	default:
+		return v * 365.25, true
+	}
+}
+
+var (
+	// abbreviatedBirthWeightRegex matches a mention introduced by "BW", which outside neonatal care usually
+	// means body weight.
+	abbreviatedBirthWeightRegex = regexp.MustCompile(`(?i)^bw\b`)
+	// neonatalContextRegex matches words that place a note in neonatal care, where "BW" is the birth weight.
+	neonatalContextRegex = regexp.MustCompile(`(?i)\b(?:neonates?|neonatal|newborn|new-born|baby|nicu|scbu|preterm|premature|gestation|apgars?|days?\s+(?:old|of\s+life)|weeks?\s+old)\b`)
+)
+
+// readBodyWeights reads a "BW" mention as the current weight unless the patient is a neonate or the note is
+// about neonatal care, so that "adult male, BW 80 kg" gives the weight rather than an impossible birth weight.
+func readBodyWeights(text string, observations []model.Observation, band ageBand) {
+	if band.name == ageBands[0].name || neonatalContextRegex.MatchString(text) {
+		return
+	}
+	for i := range observations {
+		o := &observations[i]
+		if o.Kind == model.KindBirthWeight && abbreviatedBirthWeightRegex.MatchString(o.Text) {
+			o.Kind = model.KindWeight
+		}
+	}
+}
+
+// checkPlausibility rejects a weight or height, or either bound of a range, that no age could explain: without an
+// age, one outside the fixed windows; with one, one outside the ranges of every band. A value only outside the
+// range for its band is marked implausible and held back for review, as the age may have been misread. A birth
+// weight is always checked against the neonate band, whatever the patient's age now, and held back for review
+// when outside it.
+func checkPlausibility(observations []model.Observation, band ageBand) error {
+	for i := range observations {
+		o := &observations[i]
+		band := subjectAgeBand(*o, band)
+		limits := unknownAgeBand
+		if band.name != unknownAgeBand.name {
+			limits = widest(band, anyAgeBand)
+		}
+		switch o.Kind {
+		case model.KindWeight:
+			if v, ok := outOfRange(*o, limits.weightKg); ok {
+				return fmt.Errorf("invalid weight of %g kg for age band %s", v, band.name)
+			}
+			_, o.Implausible = outOfRange(*o, band.weightKg)
+		case model.KindBirthWeight:
+			_, o.Implausible = outOfRange(*o, ageBands[0].weightKg)
+		case model.KindHeight:
+			if v, ok := outOfRange(*o, limits.heightCm); ok {
+				return fmt.Errorf("invalid height of %g cm for age band %s", v, band.name)
+			}
+			_, o.Implausible = outOfRange(*o, band.heightCm)
+		}
+	}
+
+	return nil
+}
+
+// widest returns a band spanning the ranges of both.
+func widest(a, b ageBand) ageBand {
+	return ageBand{
+		name:     a.name,
+		weightKg: [2]float64{math.Min(a.weightKg[0], b.weightKg[0]), math.Max(a.weightKg[1], b.weightKg[1])},
+		heightCm: [2]float64{math.Min(a.heightCm[0], b.heightCm[0]), math.Max(a.heightCm[1], b.heightCm[1])},
+	}
+}
+
+// outOfRange returns the first of the observation's value and range bounds that falls outside bounds, in the
+// unit of the value.
+func outOfRange(o model.Observation, bounds [2]float64) (float64, bool) {
+	values := []quantity.Quantity{o.Quantity()}
+	if o.Range != nil {
+		values = append(values, o.Range.Low, o.Range.High)
+	}
+	for _, v := range values {
+		// range bounds are as written, so bring them to the unit of the value first
+		q, err := v.To(o.Unit)
+		if err != nil {
+			continue
+		}
+		if v := q.Round(2).Value; !inRange(v, bounds) {
+			return v, true
+		}
+	}
+
+	return 0, false
+}
+
+func inRange(v float64, bounds [2]float64) bool {
+	return v >= bounds[0] && v <= bounds[1] && !math.IsNaN(v)
+}
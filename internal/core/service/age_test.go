@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForAgeBands(t *testing.T) {
+	ageDays := func(days int) *int { return &days }
+
+	tests := []struct {
+		desc            string
+		clinicalNote    *model.ClinicalNote
+		expectedAgeBand string
+		expectedWeight  float64
+		expectedHeight  float64
+		expectedReview  int
+		expectedError   error
+	}{
+		{
+			desc:            "preterm birth weight accepted for a neonate given on the request",
+			clinicalNote:    &model.ClinicalNote{Text: "weight 0.6 kg, length not recorded", AgeDays: ageDays(2)},
+			expectedAgeBand: "neonate",
+			expectedWeight:  0.6,
+		},
+		{
+			desc:            "neonate from a day old phrase",
+			clinicalNote:    &model.ClinicalNote{Text: "3 day old infant, weight 0.85kg, height 28cm"},
+			expectedAgeBand: "neonate",
+			expectedWeight:  0.85,
+			expectedHeight:  28,
+		},
+		{
+			desc:            "infant from UK months shorthand",
+			clinicalNote:    &model.ClinicalNote{Text: "2/12 old, wt 4.8 kg"},
+			expectedAgeBand: "infant",
+			expectedWeight:  4.8,
+		},
+		{
+			desc:            "infant from hyphenated weeks",
+			clinicalNote:    &model.ClinicalNote{Text: "6-week-old boy weight 4.1kg"},
+			expectedAgeBand: "infant",
+			expectedWeight:  4.1,
+		},
+		{
+			desc:            "child from years old",
+			clinicalNote:    &model.ClinicalNote{Text: "7 year old, weight 23kg"},
+			expectedAgeBand: "child",
+			expectedWeight:  23,
+		},
+		{
+			desc:            "adult from yo shorthand",
+			clinicalNote:    &model.ClinicalNote{Text: "45yo M, weight 80kg"},
+			expectedAgeBand: "adult",
+			expectedWeight:  80,
+		},
+		{
+			desc:            "request age takes precedence over the note",
+			clinicalNote:    &model.ClinicalNote{Text: "aged 45, weight 3.2kg", AgeDays: ageDays(1)},
+			expectedAgeBand: "neonate",
+			expectedWeight:  3.2,
+		},
+		{
+			desc:            "unknown age keeps the fixed window",
+			clinicalNote:    &model.ClinicalNote{Text: "weight 1.2kg"},
+			expectedAgeBand: "unknown",
+			expectedWeight:  1.2,
+		},
+		{
+			desc:            "adult weight held back for review for a neonate",
+			clinicalNote:    &model.ClinicalNote{Text: "2 day old, weight 45kg"},
+			expectedAgeBand: "neonate",
+			expectedReview:  1,
+		},
+		{
+			desc:            "neonatal weight held back for review for an adult",
+			clinicalNote:    &model.ClinicalNote{Text: "aged 60, weight 0.6 kg"},
+			expectedAgeBand: "adult",
+			expectedReview:  1,
+		},
+		{
+			desc:            "infant length held back for review above the band",
+			clinicalNote:    &model.ClinicalNote{Text: "height 120cm", AgeDays: ageDays(100)},
+			expectedAgeBand: "infant",
+			expectedReview:  1,
+		},
+		{
+			desc:          "weight no age could explain rejected",
+			clinicalNote:  &model.ClinicalNote{Text: "2 day old, weight 700kg"},
+			expectedError: errors.New("invalid weight of 700 kg for age band neonate"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAgeBand, healthMetric.AgeBand)
+			assert.Len(t, healthMetric.NeedsReview, tt.expectedReview)
+			if tt.expectedReview > 0 {
+				assert.Nil(t, healthMetric.Weight)
+				assert.Nil(t, healthMetric.Height)
+			}
+			if tt.expectedWeight != 0 {
+				require.NotNil(t, healthMetric.Weight)
+				assert.Equal(t, tt.expectedWeight, healthMetric.Weight.Value)
+			}
+			if tt.expectedHeight != 0 {
+				require.NotNil(t, healthMetric.Height)
+				assert.Equal(t, tt.expectedHeight, healthMetric.Height.Value)
+			}
+		})
+	}
+}
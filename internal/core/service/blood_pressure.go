@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+var (
+	bloodPressureRegex = regexp.MustCompile(`(?i)\b(?:blood\s+pressure|b/p|bp)\s*(?:of|is|was|at|:|=)?\s*(\d{2,3})\s*(?:/|\s+over\s+)\s*(\d{2,3})(?:\s*mm\s*hg\b)?`)
+	// slashedDateRegex matches the rest of a date such as "12/03/2025", whose day and month would otherwise be
+	// read as a blood pressure; Go regexps have no lookahead to rule it out in bloodPressureRegex itself.
+	slashedDateRegex = regexp.MustCompile(`^/\d`)
+)
+
+// apply sensible medical ranges for blood pressure
+const (
+	minSystolicMmHg  = 50.0
+	maxSystolicMmHg  = 300.0
+	minDiastolicMmHg = 20.0
+	maxDiastolicMmHg = 200.0
+)
+
+// extractBloodPressureMetrics returns a systolic and a diastolic observation for every blood pressure reading
+// in the note, in order of appearance. A reversed or out-of-range reading is marked implausible rather than
+// failing the note.
+func extractBloodPressureMetrics(text string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, m := range bloodPressureRegex.FindAllStringSubmatchIndex(text, -1) {
+		if slashedDateRegex.MatchString(text[m[1]:]) {
+			continue
+		}
+		systolic, err := strconv.ParseFloat(text[m[2]:m[3]], 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given blood pressure of %s ", text[m[0]:m[1]])
+		}
+		diastolic, err := strconv.ParseFloat(text[m[4]:m[5]], 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given blood pressure of %s ", text[m[0]:m[1]])
+		}
+		systolicObservation := newObservation(text, m[0], m[1], model.KindSystolicBP, quantity.Quantity{Value: systolic, Unit: "mm[Hg]"})
+		diastolicObservation := newObservation(text, m[0], m[1], model.KindDiastolicBP, quantity.Quantity{Value: diastolic, Unit: "mm[Hg]"})
+		implausible := !isValidBloodPressure(systolic, diastolic)
+		systolicObservation.Implausible, diastolicObservation.Implausible = implausible, implausible
+		observations = append(observations, systolicObservation, diastolicObservation)
+	}
+
+	return observations, nil
+}
+
+// selectBloodPressure pairs the primary systolic observation with the diastolic reading from the same span.
+func selectBloodPressure(observations []model.Observation) *model.BloodPressure {
+	systolic, _ := selectPrimary(observations, model.KindSystolicBP)
+	if systolic == nil {
+		return nil
+	}
+	for _, o := range observations {
+		if o.Kind == model.KindDiastolicBP && o.Start == systolic.Start {
+			return &model.BloodPressure{Systolic: systolic.Value, Diastolic: o.Value, Unit: "mm[Hg]"}
+		}
+	}
+
+	return nil
+}
+
+func isValidBloodPressure(systolic, diastolic float64) bool {
+	if math.IsNaN(systolic) || math.IsNaN(diastolic) {
+		return false
+	}
+
+	return systolic >= minSystolicMmHg && systolic <= maxSystolicMmHg &&
+		diastolic >= minDiastolicMmHg && diastolic <= maxDiastolicMmHg &&
+		systolic > diastolic
+}
@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForBloodPressure(t *testing.T) {
+	tests := []struct {
+		desc                  string
+		clinicalNote          *model.ClinicalNote
+		expectedBloodPressure *model.BloodPressure
+		expectedWeight        *quantity.Quantity
+		expectedReview        int
+	}{
+		{
+			desc:                  "no blood pressure",
+			clinicalNote:          &model.ClinicalNote{Text: "lorem ipsum dolor sit amet"},
+			expectedBloodPressure: nil,
+		},
+		{
+			desc:                  "bp with slash",
+			clinicalNote:          &model.ClinicalNote{Text: "BP 120/80, HR regular"},
+			expectedBloodPressure: &model.BloodPressure{Systolic: 120, Diastolic: 80, Unit: "mm[Hg]"},
+		},
+		{
+			desc:                  "b/p with colon and unit",
+			clinicalNote:          &model.ClinicalNote{Text: "b/p: 135/85 mmHg"},
+			expectedBloodPressure: &model.BloodPressure{Systolic: 135, Diastolic: 85, Unit: "mm[Hg]"},
+		},
+		{
+			desc:                  "bp written as over",
+			clinicalNote:          &model.ClinicalNote{Text: "BP 120 over 80"},
+			expectedBloodPressure: &model.BloodPressure{Systolic: 120, Diastolic: 80, Unit: "mm[Hg]"},
+		},
+		{
+			desc:                  "blood pressure in words",
+			clinicalNote:          &model.ClinicalNote{Text: "blood pressure was 142/91mmHg"},
+			expectedBloodPressure: &model.BloodPressure{Systolic: 142, Diastolic: 91, Unit: "mm[Hg]"},
+		},
+		{
+			desc:                  "today outranks on admission",
+			clinicalNote:          &model.ClinicalNote{Text: "on admission BP 180/100; today BP 130/82"},
+			expectedBloodPressure: &model.BloodPressure{Systolic: 130, Diastolic: 82, Unit: "mm[Hg]"},
+		},
+		{
+			desc:           "diastolic above systolic held back for review",
+			clinicalNote:   &model.ClinicalNote{Text: "BP 80/120, weight 80kg"},
+			expectedWeight: qty(80, "kg"),
+			expectedReview: 2,
+		},
+		{
+			desc:           "systolic out of range held back for review",
+			clinicalNote:   &model.ClinicalNote{Text: "BP 320/80"},
+			expectedReview: 2,
+		},
+		{
+			desc:           "date is not a blood pressure",
+			clinicalNote:   &model.ClinicalNote{Text: "BP 12/03/2025 weight 80kg"},
+			expectedWeight: qty(80, "kg"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBloodPressure, healthMetric.BloodPressure)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			assert.Len(t, healthMetric.NeedsReview, tt.expectedReview)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+var bmiRegex = regexp.MustCompile(`(?i)\bBMI\s*(?:of|is|was|:|=)?\s*(\d{1,2}(?:\.\d{1,2})?)\b`)
+
+const (
+	minBMI = 7.0
+	maxBMI = 99.0
+	// bmiMismatchTolerance is the largest gap between a stated and computed BMI that we put down to rounding.
+	bmiMismatchTolerance = 1.0
+)
+
+type bmiCategory struct {
+	below float64
+	name  string
+}
+
+// bmiCutOffs holds the WHO adult categories and the lower thresholds NICE recommends for South Asian patients.
+var bmiCutOffs = map[string][]bmiCategory{
+	model.BMICutOffsWHO: {
+		{below: 18.5, name: "underweight"},
+		{below: 25, name: "healthy weight"},
+		{below: 30, name: "overweight"},
+		{below: 35, name: "obese class I"},
+		{below: 40, name: "obese class II"},
+		{below: math.Inf(1), name: "obese class III"},
+	},
+	model.BMICutOffsSouthAsian: {
+		{below: 18.5, name: "underweight"},
+		{below: 23, name: "healthy weight"},
+		{below: 27.5, name: "overweight"},
+		{below: 32.5, name: "obese class I"},
+		{below: 37.5, name: "obese class II"},
+		{below: math.Inf(1), name: "obese class III"},
+	},
+}
+
+// extractBMIMetrics returns every BMI stated directly in the note, in order of appearance. An implausible BMI is
+// marked rather than failing the note.
+func extractBMIMetrics(text string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, m := range bmiRegex.FindAllStringSubmatchIndex(text, -1) {
+		valStr := text[m[2]:m[3]]
+		v, err := strconv.ParseFloat(valStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given bmi of %s ", valStr)
+		}
+		observation := newObservation(text, m[0], m[1], model.KindBMI, quantity.Quantity{Value: v, Unit: "kg/m2"})
+		observation.Implausible = !isValidBMI(v)
+		observations = append(observations, observation)
+	}
+
+	return observations, nil
+}
+
+// deriveBMI computes BMI from the primary weight and height, falling back to a stated BMI when either is
+// missing, and flags a stated BMI that disagrees with the computed one.
+func deriveBMI(weight, height, stated *model.Observation, cutOffs string) *model.BMI {
+	if cutOffs == "" {
+		cutOffs = model.BMICutOffsWHO
+	}
+
+	var bmi *model.BMI
+	switch {
+	case weight != nil && height != nil:
+		metres := height.Value / 100
+		bmi = &model.BMI{Value: round(weight.Value/(metres*metres), 1), Source: model.BMISourceComputed}
+	case stated != nil:
+		bmi = &model.BMI{Value: stated.Value, Source: model.BMISourceStated}
+	default:
+		return nil
+	}
+
+	bmi.CutOffs = cutOffs
+	bmi.Category = bmiCategoryFor(bmi.Value, cutOffs)
+	if stated != nil {
+		value := stated.Value
+		bmi.Stated = &value
+		bmi.Mismatch = math.Abs(bmi.Value-value) > bmiMismatchTolerance
+	}
+
+	return bmi
+}
+
+func bmiCategoryFor(bmi float64, cutOffs string) string {
+	for _, category := range bmiCutOffs[cutOffs] {
+		if bmi < category.below {
+			return category.name
+		}
+	}
+
+	return ""
+}
+
+func isValidBMI(v float64) bool {
+	return v >= minBMI && v <= maxBMI && !math.IsNaN(v)
+}
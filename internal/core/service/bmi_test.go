@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForBMI(t *testing.T) {
+	stated := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		desc         string
+		clinicalNote *model.ClinicalNote
+		expectedBMI  *model.BMI
+	}{
+		{
+			desc:         "no bmi without both weight and height",
+			clinicalNote: &model.ClinicalNote{Text: "weight 80kg"},
+			expectedBMI:  nil,
+		},
+		{
+			desc:         "computed from weight and height",
+			clinicalNote: &model.ClinicalNote{Text: "weight 80kg, height 180cm"},
+			expectedBMI:  &model.BMI{Value: 24.7, Category: "healthy weight", CutOffs: "who", Source: "computed"},
+		},
+		{
+			desc:         "computed from imperial weight and height",
+			clinicalNote: &model.ClinicalNote{Text: `wt 13 st 2 lb, ht 5'7"`},
+			expectedBMI:  &model.BMI{Value: 28.8, Category: "overweight", CutOffs: "who", Source: "computed"},
+		},
+		{
+			desc:         "south asian cut-offs lower the category thresholds",
+			clinicalNote: &model.ClinicalNote{Text: "weight 80kg, height 180cm", BMICutOffs: "south_asian"},
+			expectedBMI:  &model.BMI{Value: 24.7, Category: "overweight", CutOffs: "south_asian", Source: "computed"},
+		},
+		{
+			desc:         "who obesity class",
+			clinicalNote: &model.ClinicalNote{Text: "weight 125kg, height 170cm"},
+			expectedBMI:  &model.BMI{Value: 43.3, Category: "obese class III", CutOffs: "who", Source: "computed"},
+		},
+		{
+			desc:         "stated bmi agreeing with the computed value",
+			clinicalNote: &model.ClinicalNote{Text: "weight 80kg, height 180cm, BMI 24.7"},
+			expectedBMI:  &model.BMI{Value: 24.7, Category: "healthy weight", CutOffs: "who", Source: "computed", Stated: stated(24.7)},
+		},
+		{
+			desc:         "stated bmi disagreeing with the computed value is flagged",
+			clinicalNote: &model.ClinicalNote{Text: "weight 80kg, height 180cm, BMI 31.2"},
+			expectedBMI:  &model.BMI{Value: 24.7, Category: "healthy weight", CutOffs: "who", Source: "computed", Stated: stated(31.2), Mismatch: true},
+		},
+		{
+			desc:         "stated bmi used when height is missing",
+			clinicalNote: &model.ClinicalNote{Text: "weight 95kg. BMI: 31.2"},
+			expectedBMI:  &model.BMI{Value: 31.2, Category: "obese class I", CutOffs: "who", Source: "stated", Stated: stated(31.2)},
+		},
+		{
+			desc:         "implausible stated bmi held back for review",
+			clinicalNote: &model.ClinicalNote{Text: "BMI 5"},
+			expectedBMI:  nil,
+		},
+		{
+			desc:         "implausible stated bmi is not compared with the computed value",
+			clinicalNote: &model.ClinicalNote{Text: "weight 80kg, height 180cm, BMI 5"},
+			expectedBMI:  &model.BMI{Value: 24.7, Category: "healthy weight", CutOffs: "who", Source: "computed"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBMI, healthMetric.BMI)
+		})
+	}
+}
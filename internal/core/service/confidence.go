@@ -0,0 +1,161 @@
+package service
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+// confidenceWeights sum to one, so that an observation scoring fully on every factor has a confidence of 1.
+const (
+	keywordWeight      = 0.15
+	unitWeight         = 0.2
+	contextWeight      = 0.2
+	sectionWeight      = 0.15
+	qualifierWeight    = 0.15
+	plausibilityWeight = 0.15
+)
+
+var (
+	// weakKeywords introduce a value less reliably than the full metric name, e.g. "weighs" in a social history or
+	// "patient is 45kg".
+	weakKeywords = map[string]bool{"wt": true, "ht": true, "bw": true, "weighs": true, "resps": true, "sats": true,
+		"bm": true, "cr": true, "patient": true, "pt": true}
+	// unitTokenRegex splits the text after a value into the tokens that might spell its unit.
+	unitTokenRegex = regexp.MustCompile(`[^\s\d.,:;=~≈()-]+`)
+)
+
+// scoreConfidence sets the confidence of every observation, from 0 to 1, on how it was written and where.
+// Observations must already be tagged, ranked and annotated.
+func scoreConfidence(observations []model.Observation, band ageBand) {
+	for i := range observations {
+		o := &observations[i]
+		confidence := keywordWeight*keywordStrength(*o) +
+			unitWeight*unitPresence(*o) +
+			contextWeight*contextStrength(*o) +
+			sectionWeight*sectionWeightings[o.Section].reliability +
+			qualifierWeight*qualifierStrength(*o) +
+			plausibilityWeight*plausibilityMargin(*o, subjectAgeBand(*o, band))
+		o.Confidence = round(confidence, 2)
+	}
+}
+
+// flagForReview marks the observations below the review threshold, which keeps them out of primary selection.
+func flagForReview(observations []model.Observation, threshold float64) []model.Observation {
+	var flagged []model.Observation
+	for i := range observations {
+		o := &observations[i]
+		o.NeedsReview = o.Confidence < threshold || o.Implausible
+		if o.NeedsReview {
+			flagged = append(flagged, *o)
+		}
+	}
+
+	return flagged
+}
+
+// reviewStatus explains a missing primary value of the given kind when a mention was held back for review.
+func reviewStatus(status string, selected *model.Observation, observations []model.Observation, kind string) string {
+	if status != "" || selected != nil {
+		return status
+	}
+	for _, o := range observations {
+		if o.Kind == kind && o.NeedsReview && !o.Negated && !o.Historical && isPatient(o) {
+			return model.StatusNeedsReview
+		}
+	}
+
+	return status
+}
+
+func keywordStrength(o model.Observation) float64 {
+	keyword := strings.ToLower(strings.TrimRight(strings.Fields(o.Text + " ")[0], ":=."))
+	if weakKeywords[keyword] {
+		return 0.6
+	}
+
+	return 1
+}
+
+// unitPresence scores a unit written after the value above one inferred from the kind or magnitude.
+func unitPresence(o model.Observation) float64 {
+	after := o.Text
+	if i := strings.IndexFunc(o.Text, unicode.IsDigit); i >= 0 {
+		after = o.Text[i:]
+	}
+	for _, token := range unitTokenRegex.FindAllString(after, -1) {
+		if _, err := quantity.Parse(token); err == nil {
+			return 1
+		}
+	}
+
+	return 0.5
+}
+
+// contextStrength maps the ranking score from the cue words alone, from the target cue at -4 to the today cue at
+// 4, onto 0 to 1. The section is weighed separately.
+func contextStrength(o model.Observation) float64 {
+	score := o.Score - sectionWeightings[o.Section].priority
+	return math.Max(0, math.Min(1, float64(score+4)/8))
+}
+
+// qualifierStrength discounts values the note hedges: uncertain, approximate or written as a range.
+func qualifierStrength(o model.Observation) float64 {
+	strength := 1.0
+	if o.Uncertain {
+		strength *= 0.4
+	}
+	if o.Estimated {
+		strength *= 0.6
+	}
+	if o.Range != nil {
+		strength *= 0.7
+	}
+
+	return strength
+}
+
+// plausibilityMargin discounts values within a tenth of the plausible range of either limit, falling to zero at
+// the limit itself.
+func plausibilityMargin(o model.Observation, band ageBand) float64 {
+	bounds, ok := plausibleBounds(o, band)
+	if !ok {
+		return 1
+	}
+	margin := math.Min(o.Value-bounds[0], bounds[1]-o.Value) / (0.1 * (bounds[1] - bounds[0]))
+
+	return math.Max(0, math.Min(1, margin))
+}
+
+// plausibleBounds returns the range each extractor validates the kind against, in the unit of the observation.
+func plausibleBounds(o model.Observation, band ageBand) ([2]float64, bool) {
+	switch o.Kind {
+	case model.KindWeight:
+		return band.weightKg, true
+	case model.KindBirthWeight:
+		return ageBands[0].weightKg, true
+	case model.KindHeight:
+		return band.heightCm, true
+	case model.KindBMI:
+		return [2]float64{minBMI, maxBMI}, true
+	case model.KindSystolicBP:
+		return [2]float64{minSystolicMmHg, maxSystolicMmHg}, true
+	case model.KindDiastolicBP:
+		return [2]float64{minDiastolicMmHg, maxDiastolicMmHg}, true
+	case model.KindHeartRate:
+		return [2]float64{minHeartRateBpm, maxHeartRateBpm}, true
+	case model.KindRespiratoryRate:
+		return [2]float64{minRespiratoryRatePerMin, maxRespiratoryRatePerMin}, true
+	case model.KindTemperature:
+		return [2]float64{minTemperatureC, maxTemperatureC}, true
+	}
+	if a, ok := lookupAnalyte(o.Kind); ok {
+		return a.plausible, true
+	}
+
+	return [2]float64{}, false
+}
@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+	"cleo.com/internal/core/service"
+	"cleo.com/testsupport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForConfidence(t *testing.T) {
+	tests := []struct {
+		desc               string
+		clinicalNote       *model.ClinicalNote
+		expectedConfidence []float64
+	}{
+		{
+			desc:               "labelled weight with a unit in a vitals block",
+			clinicalNote:       &model.ClinicalNote{Text: "Vitals:\nWeight: 80 kg"},
+			expectedConfidence: []float64{0.95},
+		},
+		{
+			desc:               "approximate weight in a social history",
+			clinicalNote:       &model.ClinicalNote{Text: "Social history:\nweighs about 80 kg"},
+			expectedConfidence: []float64{0.65},
+		},
+		{
+			desc:               "heart rate without a unit outside any section",
+			clinicalNote:       &model.ClinicalNote{Text: "HR 72"},
+			expectedConfidence: []float64{0.82},
+		},
+		{
+			desc:               "value close to the plausible limit",
+			clinicalNote:       &model.ClinicalNote{Text: "weight 0.6 kg", AgeDays: func(d int) *int { return &d }(2)},
+			expectedConfidence: []float64{0.84},
+		},
+		{
+			desc:               "uncertain weight range",
+			clinicalNote:       &model.ClinicalNote{Text: "weight 70-72kg?"},
+			expectedConfidence: []float64{0.81},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			var confidence []float64
+			for _, o := range healthMetric.Observations {
+				confidence = append(confidence, o.Confidence)
+			}
+			assert.Equal(t, tt.expectedConfidence, confidence)
+			assert.Empty(t, healthMetric.NeedsReview)
+		})
+	}
+}
+
+func TestParserService_ParseClinicalNote_ForReviewThreshold(t *testing.T) {
+	tests := []struct {
+		desc                 string
+		clinicalNote         *model.ClinicalNote
+		expectedWeight       *quantity.Quantity
+		expectedWeightStatus string
+		expectedNeedsReview  []string
+	}{
+		{
+			desc:                 "only mention is held back for review",
+			clinicalNote:         &model.ClinicalNote{Text: "Social history:\nweighs about 80 kg"},
+			expectedWeightStatus: model.StatusNeedsReview,
+			expectedNeedsReview:  []string{"weighs about 80 kg"},
+		},
+		{
+			desc:                "confident mention is reported over one held back",
+			clinicalNote:        &model.ClinicalNote{Text: "Vitals:\nweight 82kg\nSocial history:\nweighs about 80 kg"},
+			expectedWeight:      qty(82, "kg"),
+			expectedNeedsReview: []string{"weighs about 80 kg"},
+		},
+		{
+			desc:           "confident mention alone",
+			clinicalNote:   &model.ClinicalNote{Text: "Weight: 80 kg"},
+			expectedWeight: qty(80, "kg"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService, err := service.NewParserService(testsupport.Logger(), service.Config{ReviewThreshold: 0.7}, service.DefaultExtractorRegistry())
+			require.NoError(t, err)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			assert.Equal(t, tt.expectedWeightStatus, healthMetric.WeightStatus)
+			var needsReview []string
+			for _, o := range healthMetric.NeedsReview {
+				needsReview = append(needsReview, o.Text)
+			}
+			assert.Equal(t, tt.expectedNeedsReview, needsReview)
+		})
+	}
+}
+
+func TestNewParserService_RejectsReviewThresholdOutOfRange(t *testing.T) {
+	_, err := service.NewParserService(testsupport.Logger(), service.Config{ReviewThreshold: 1.5}, service.DefaultExtractorRegistry())
+
+	assert.EqualError(t, err, "review threshold of 1.5 is outside 0 to 1 in parser config")
+}
@@ -0,0 +1,27 @@
+package service
+
+// Config holds the deployment settings of the parser.
+type Config struct {
+	// EnabledExtractors limits parsing to the named extractors; when empty every registered extractor runs.
+	EnabledExtractors []string `env:"PARSER_ENABLED_EXTRACTORS"`
+	// DisabledExtractors removes the named extractors from whatever is enabled.
+	DisabledExtractors []string `env:"PARSER_DISABLED_EXTRACTORS"`
+	// ReviewThreshold is the confidence below which an observation is returned as needing review rather than
+	// reported as a primary value. Zero reports every observation.
+	ReviewThreshold float64 `env:"PARSER_REVIEW_THRESHOLD, default=0.5"`
+	// DoseTolerance is the fraction by which an absolute dose written beside a per-kg dose may differ from the
+	// per-kg dose for the primary weight before it is flagged.
+	DoseTolerance float64 `env:"PARSER_DOSE_TOLERANCE, default=0.1"`
+	// LexiconFile is a JSON lexicon of the site's weight and height keywords and unit spellings, used in place of
+	// the built-in one for its language when set.
+	LexiconFile string `env:"PARSER_LEXICON_FILE"`
+	// DateOrder reads a slashed date such as 12/03/2025 day first (dmy) or month first (mdy). Empty reads it day
+	// first.
+	DateOrder string `env:"PARSER_DATE_ORDER, default=dmy"`
+}
+
+// Orders a slashed date may be read in.
+const (
+	DateOrderDayFirst   = "dmy"
+	DateOrderMonthFirst = "mdy"
+)
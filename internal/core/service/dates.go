@@ -0,0 +1,344 @@
+package service
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+const (
+	monthPattern = `jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sept?(?:ember)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?`
+	// timePattern captures the time of day that may follow a date: "at 08:30" or "14:05".
+	timePattern = `(?:,?\s+(?:at\s+)?(\d{1,2})[:.](\d{2})\b)?`
+)
+
+// dateMention is a date or relative date phrase in the note, resolved to the time it refers to.
+type dateMention struct {
+	start, end int
+	at         time.Time
+}
+
+// datePattern recognises one way of writing a date. resolve is given the submatches, the last two of which are
+// the hour and minute of any time after it, and returns false when the text is not a date it can place.
+type datePattern struct {
+	pattern *regexp.Regexp
+	resolve func(m []string, dates dateContext) (time.Time, bool)
+}
+
+// dateContext holds what a written date is read against: the order of a slashed date and the time the note was
+// written, without which relative phrases cannot be placed.
+type dateContext struct {
+	order     string
+	reference *time.Time
+	location  *time.Location
+}
+
+var datePatterns = []datePattern{
+	{pattern: regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b` + timePattern), resolve: resolveISODate},
+	{pattern: regexp.MustCompile(`\b(\d{1,2})([/.])(\d{1,2})[/.](\d{4}|\d{2})\b` + timePattern), resolve: resolveNumericDate},
+	{pattern: regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\s+(` + monthPattern + `)\.?,?\s+(\d{4})\b` + timePattern), resolve: resolveDayMonthDate},
+	{pattern: regexp.MustCompile(`(?i)\b(` + monthPattern + `)\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b` + timePattern), resolve: resolveMonthDayDate},
+	{pattern: regexp.MustCompile(`(?i)\b(?:(?:the\s+)?(day\s+before\s+yesterday)|(yesterday)|(today|this\s+(?:morning|afternoon|evening)|tonight))\b` + timePattern), resolve: resolveRelativeDay},
+	{pattern: regexp.MustCompile(`(?i)\b(\d{1,3}|an?|one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve)\s+(day|week|month|year)s?\s+ago\b` + timePattern), resolve: resolveAgo},
+	{pattern: regexp.MustCompile(`(?i)\blast\s+(week|month|year)\b` + timePattern), resolve: resolveLast},
+}
+
+func newDateContext(order string, reference *time.Time) dateContext {
+	dates := dateContext{order: order, reference: reference, location: time.UTC}
+	if reference != nil {
+		dates.location = reference.Location()
+	}
+
+	return dates
+}
+
+// repeatRegex matches a value with a weight or height unit straight after a date, as in "today 79kg".
+var repeatRegex = regexp.MustCompile(`(?i)^\s*:?\s*(\d{1,4}(?:\.\d{1,2})?)\s*(kgs?|g|lbs?|cm|m)\b`)
+
+// readDatedRepeats returns the observations with a weight or height written straight after a date added, when the
+// note names that metric earlier, so that "On 12/03/2025 weight 82kg; today 79kg" reads both weights.
+func readDatedRepeats(text string, observations []model.Observation, mentions []dateMention) []model.Observation {
+	kinds := map[quantity.Dimension]string{quantity.Mass: model.KindWeight, quantity.Length: model.KindHeight}
+	for _, d := range mentions {
+		m := repeatRegex.FindStringSubmatchIndex(text[d.end:])
+		if m == nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(text[d.end+m[2]:d.end+m[3]], 64)
+		if err != nil {
+			continue
+		}
+		unit, err := quantity.Parse(text[d.end+m[4] : d.end+m[5]])
+		if err != nil {
+			continue
+		}
+		kind := kinds[unit.Dimension]
+		if kind == "" || !namedBefore(observations, kind, d.start) {
+			continue
+		}
+		written := quantity.Quantity{Value: v, Unit: unit.Code}
+		canonical, err := written.To(map[string]string{model.KindWeight: "kg", model.KindHeight: "cm"}[kind])
+		if err != nil {
+			continue
+		}
+		observation := newObservation(text, d.start, d.end+m[1], kind, canonical.Round(outputPrecision[kind]))
+		if overlapsObservation(observations, observation) {
+			continue
+		}
+		observation.Written = &written
+		observations = append(observations, observation)
+	}
+
+	return observations
+}
+
+func namedBefore(observations []model.Observation, kind string, offset int) bool {
+	for _, o := range observations {
+		if o.Kind == kind && o.End <= offset {
+			return true
+		}
+	}
+
+	return false
+}
+
+// attachDates gives each observation the date written in its mention, or failing that the nearest date in the
+// phrase around it, or failing that a date leading its clause, so that "on 12/03/2025 weight 82kg,
+// height 180cm" dates both.
+func attachDates(text string, observations []model.Observation, mentions []dateMention) {
+	spans := observationSpans(observations)
+	for i := range observations {
+		o := &observations[i]
+		o.EffectiveTime = nil
+		if d := effectiveDate(text, *o, mentions, spans); d != nil {
+			at := d.at
+			o.EffectiveTime = &at
+		}
+	}
+}
+
+func effectiveDate(text string, o model.Observation, mentions []dateMention, spans [][2]int) *dateMention {
+	from, to := contextBounds(text, o.Start, o.End, spans, trailingBoundaryRegex)
+	var nearest *dateMention
+	nearestDistance := len(text)
+	for i := range mentions {
+		d := &mentions[i]
+		if d.start >= o.Start && d.end <= o.End {
+			return d
+		}
+		if d.start < from || d.end > to {
+			continue
+		}
+		if distance := max(o.Start-d.end, d.start-o.End); distance < nearestDistance {
+			nearest, nearestDistance = d, distance
+		}
+	}
+	if nearest != nil {
+		return nearest
+	}
+
+	clause := 0
+	if bs := clauseBoundaryRegex.FindAllStringIndex(text[:o.Start], -1); len(bs) > 0 {
+		clause = bs[len(bs)-1][1]
+	}
+	for i := len(mentions) - 1; i >= 0; i-- {
+		if mentions[i].start >= clause && mentions[i].end <= o.Start && !mentionedBetween(spans, clause, mentions[i].start) {
+			return &mentions[i]
+		}
+	}
+
+	return nil
+}
+
+// mentionedBetween reports whether a mention ends within from:to, so that a date only carries over to later
+// mentions in its clause when it leads it.
+func mentionedBetween(spans [][2]int, from, to int) bool {
+	for _, span := range spans {
+		if span[0] >= from && span[1] <= to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isFuture reports whether the time falls on a day after the note was written.
+func (dates dateContext) isFuture(at time.Time) bool {
+	if dates.reference == nil {
+		return false
+	}
+	reference := dates.reference.In(dates.location)
+	endOfDay := time.Date(reference.Year(), reference.Month(), reference.Day()+1, 0, 0, 0, 0, dates.location)
+
+	return !at.Before(endOfDay)
+}
+
+// findDates returns every date the note mentions, in order of appearance, leaving out any after the day the note
+// was written, such as the date of a target. Where two patterns match the same text the earlier pattern wins.
+func findDates(text string, dates dateContext) []dateMention {
+	var mentions []dateMention
+	for _, p := range datePatterns {
+		for _, m := range p.pattern.FindAllStringSubmatchIndex(text, -1) {
+			if overlapsDate(mentions, m[0], m[1]) {
+				continue
+			}
+			at, ok := p.resolve(submatches(text, m), dates)
+			if !ok || dates.isFuture(at) {
+				continue
+			}
+			mentions = append(mentions, dateMention{start: m[0], end: m[1], at: at})
+		}
+	}
+	sort.Slice(mentions, func(i, j int) bool { return mentions[i].start < mentions[j].start })
+
+	return mentions
+}
+
+// withoutDates returns text[from:to] with every date mention in it blanked out, so that "2 days ago" or "last
+// week", once placed in time, is not also read as a cue that the value is old.
+func withoutDates(text string, from, to int, mentions []dateMention) string {
+	window := []byte(text[from:to])
+	for _, d := range mentions {
+		for i := max(d.start, from); i < min(d.end, to); i++ {
+			window[i-from] = ' '
+		}
+	}
+
+	return string(window)
+}
+
+func submatches(text string, m []int) []string {
+	groups := make([]string, len(m)/2)
+	for i := range groups {
+		if m[2*i] >= 0 {
+			groups[i] = text[m[2*i]:m[2*i+1]]
+		}
+	}
+
+	return groups
+}
+
+func overlapsDate(mentions []dateMention, start, end int) bool {
+	for _, d := range mentions {
+		if start < d.end && d.start < end {
+			return true
+		}
+	}
+
+	return false
+}
+
+// calendarDate returns the date, at any time of day given in the last two submatches, or false when it is not on
+// the calendar, such as 31/02/2025.
+func calendarDate(year, month, day int, m []string, dates dateContext) (time.Time, bool) {
+	hour, minute := 0, 0
+	if m[len(m)-2] != "" {
+		hour, _ = strconv.Atoi(m[len(m)-2])
+		minute, _ = strconv.Atoi(m[len(m)-1])
+		if hour > 23 || minute > 59 {
+			return time.Time{}, false
+		}
+	}
+	at := time.Date(year, time.Month(month), day, hour, minute, 0, 0, dates.location)
+	if at.Year() != year || at.Month() != time.Month(month) || at.Day() != day {
+		return time.Time{}, false
+	}
+
+	return at, true
+}
+
+func resolveISODate(m []string, dates dateContext) (time.Time, bool) {
+	year, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+
+	return calendarDate(year, month, day, m, dates)
+}
+
+// resolveNumericDate reads a slashed date in the configured order. A dotted date is always read day first, as
+// the languages that write it do.
+func resolveNumericDate(m []string, dates dateContext) (time.Time, bool) {
+	day, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[3])
+	if m[2] == "/" && dates.order == DateOrderMonthFirst {
+		day, month = month, day
+	}
+	year, _ := strconv.Atoi(m[4])
+	if len(m[4]) == 2 {
+		year += 2000
+	}
+
+	return calendarDate(year, month, day, m, dates)
+}
+
+func resolveDayMonthDate(m []string, dates dateContext) (time.Time, bool) {
+	day, _ := strconv.Atoi(m[1])
+	year, _ := strconv.Atoi(m[3])
+
+	return calendarDate(year, monthNumber(m[2]), day, m, dates)
+}
+
+func resolveMonthDayDate(m []string, dates dateContext) (time.Time, bool) {
+	day, _ := strconv.Atoi(m[2])
+	year, _ := strconv.Atoi(m[3])
+
+	return calendarDate(year, monthNumber(m[1]), day, m, dates)
+}
+
+func monthNumber(name string) int {
+	return strings.Index("janfebmaraprmayjunjulaugsepoctnovdec", strings.ToLower(name[:3]))/3 + 1
+}
+
+func resolveRelativeDay(m []string, dates dateContext) (time.Time, bool) {
+	days := 0
+	switch {
+	case m[1] != "":
+		days = 2
+	case m[2] != "":
+		days = 1
+	}
+
+	return relativeDate(0, 0, -days, m, dates)
+}
+
+func resolveAgo(m []string, dates dateContext) (time.Time, bool) {
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		n = max(1, int(numberWordValues[strings.ToLower(m[1])]))
+	}
+
+	return relativeUnits(-n, m[2], m, dates)
+}
+
+func resolveLast(m []string, dates dateContext) (time.Time, bool) {
+	return relativeUnits(-1, m[1], m, dates)
+}
+
+func relativeUnits(n int, unit string, m []string, dates dateContext) (time.Time, bool) {
+	switch strings.ToLower(unit) {
+	case "year":
+		return relativeDate(n, 0, 0, m, dates)
+	case "month":
+		return relativeDate(0, n, 0, m, dates)
+	case "week":
+		return relativeDate(0, 0, 7*n, m, dates)
+	default:
+		return relativeDate(0, 0, n, m, dates)
+	}
+}
+
+// relativeDate returns the day the given years, months and days from the day the note was written, or false when
+// the note has no reference time.
+func relativeDate(years, months, days int, m []string, dates dateContext) (time.Time, bool) {
+	if dates.reference == nil {
+		return time.Time{}, false
+	}
+	day := dates.reference.In(dates.location).AddDate(years, months, days)
+
+	return calendarDate(day.Year(), int(day.Month()), day.Day(), m, dates)
+}
@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+	"cleo.com/internal/core/service"
+	"cleo.com/testsupport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForEffectiveTimes(t *testing.T) {
+	reference := time.Date(2025, time.April, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		desc           string
+		config         service.Config
+		clinicalNote   *model.ClinicalNote
+		expectedWeight *quantity.Quantity
+		expectedTimes  []string
+	}{
+		{
+			desc:           "absolute and relative dates against the reference time",
+			clinicalNote:   &model.ClinicalNote{Text: "On 12/03/2025 weight 82kg; today 79kg", ReferenceTime: &reference},
+			expectedWeight: qty(79, "kg"),
+			expectedTimes:  []string{"2025-03-12T00:00:00Z", "2025-04-01T00:00:00Z"},
+		},
+		{
+			desc:           "most recent dated measurement preferred over a stronger cue",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 82kg measured on admission 12/03/2025, weight 79kg on 2025-03-20"},
+			expectedWeight: qty(79, "kg"),
+			expectedTimes:  []string{"2025-03-12T00:00:00Z", "2025-03-20T00:00:00Z"},
+		},
+		{
+			desc:           "slashed date read month first",
+			config:         service.Config{DateOrder: service.DateOrderMonthFirst},
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg on 03/12/2025"},
+			expectedWeight: qty(80, "kg"),
+			expectedTimes:  []string{"2025-03-12T00:00:00Z"},
+		},
+		{
+			desc:           "dotted date read day first whatever the order",
+			config:         service.Config{DateOrder: service.DateOrderMonthFirst},
+			clinicalNote:   &model.ClinicalNote{Text: "Gewicht 80 kg am 03.12.2025"},
+			expectedWeight: qty(80, "kg"),
+			expectedTimes:  []string{"2025-12-03T00:00:00Z"},
+		},
+		{
+			desc:           "written month with a time of day",
+			clinicalNote:   &model.ClinicalNote{Text: "12 March 2025 at 08:30 weight 80kg"},
+			expectedWeight: qty(80, "kg"),
+			expectedTimes:  []string{"2025-03-12T08:30:00Z"},
+		},
+		{
+			desc:           "date earlier in the clause dates every mention in it",
+			clinicalNote:   &model.ClinicalNote{Text: "March 12, 2025: weight 80kg, height 180cm"},
+			expectedWeight: qty(80, "kg"),
+			expectedTimes:  []string{"2025-03-12T00:00:00Z", "2025-03-12T00:00:00Z"},
+		},
+		{
+			desc:           "yesterday",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg yesterday", ReferenceTime: &reference},
+			expectedWeight: qty(80, "kg"),
+			expectedTimes:  []string{"2025-03-31T00:00:00Z"},
+		},
+		{
+			desc:           "lone historical weight reported for its date",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg 3 weeks ago", ReferenceTime: &reference},
+			expectedWeight: qty(80, "kg"),
+			expectedTimes:  []string{"2025-03-11T00:00:00Z"},
+		},
+		{
+			desc:           "relative date more recent than an absolute one",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg on 01/01/2025; weight 82kg 2 days ago", ReferenceTime: &reference},
+			expectedWeight: qty(82, "kg"),
+			expectedTimes:  []string{"2025-01-01T00:00:00Z", "2025-03-30T00:00:00Z"},
+		},
+		{
+			desc:           "last week more recent than an absolute date",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg on 01/01/2025; weight 82kg last week", ReferenceTime: &reference},
+			expectedWeight: qty(82, "kg"),
+			expectedTimes:  []string{"2025-01-01T00:00:00Z", "2025-03-25T00:00:00Z"},
+		},
+		{
+			desc:           "weight dated weeks ago gives way to an undated current one",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg 3 weeks ago, weight 78kg", ReferenceTime: &reference},
+			expectedWeight: qty(78, "kg"),
+			expectedTimes:  []string{"2025-03-11T00:00:00Z", ""},
+		},
+		{
+			desc:           "date after the reference time is left out",
+			clinicalNote:   &model.ClinicalNote{Text: "target weight 70kg by 01/09/2025, weight 80kg on 01/03/2025", ReferenceTime: &reference},
+			expectedWeight: qty(80, "kg"),
+			expectedTimes:  []string{"", "2025-03-01T00:00:00Z"},
+		},
+		{
+			desc:           "target does not win on its date",
+			clinicalNote:   &model.ClinicalNote{Text: "target weight 70kg by 01/09/2025, weight 80kg on 01/06/2025"},
+			expectedWeight: qty(80, "kg"),
+			expectedTimes:  []string{"2025-09-01T00:00:00Z", "2025-06-01T00:00:00Z"},
+		},
+		{
+			desc:           "relative date without a reference time is left undated",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg yesterday"},
+			expectedWeight: qty(80, "kg"),
+			expectedTimes:  []string{""},
+		},
+		{
+			desc:           "date not on the calendar is left undated",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg on 31/02/2025"},
+			expectedWeight: qty(80, "kg"),
+			expectedTimes:  []string{""},
+		},
+		{
+			desc:          "time zone of the reference time",
+			clinicalNote:  &model.ClinicalNote{Text: "HbA1c 48 mmol/mol today", ReferenceTime: func(t time.Time) *time.Time { return &t }(reference.In(time.FixedZone("NZDT", 13*3600)))},
+			expectedTimes: []string{"2025-04-01T00:00:00+13:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService, err := service.NewParserService(testsupport.Logger(), tt.config, service.DefaultExtractorRegistry())
+			require.NoError(t, err)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			require.Len(t, healthMetric.Observations, len(tt.expectedTimes))
+			for i, expected := range tt.expectedTimes {
+				actual := ""
+				if at := healthMetric.Observations[i].EffectiveTime; at != nil {
+					actual = at.Format(time.RFC3339)
+				}
+				assert.Equal(t, expected, actual)
+			}
+		})
+	}
+}
+
+func TestParserService_ParseClinicalNote_ExplainsMostRecentDated(t *testing.T) {
+	testService := newTestParserService(t)
+	healthMetric, err := testService.ParseClinicalNote(&model.ClinicalNote{Text: "weight 82kg on 12/03/2025, weight 79kg on 20/03/2025"})
+
+	require.NoError(t, err)
+	assert.Equal(t, qty(79, "kg"), healthMetric.Weight)
+	assert.Equal(t, "ranked highest of 2 candidates (no context cues); most recent of 2 dated candidates", healthMetric.WeightReason)
+}
+
+func TestParserService_ParseClinicalNote_DoesNotExplainTargetByDate(t *testing.T) {
+	testService := newTestParserService(t)
+	healthMetric, err := testService.ParseClinicalNote(&model.ClinicalNote{Text: "target weight 70kg by 01/09/2025, weight 80kg on 01/06/2025"})
+
+	require.NoError(t, err)
+	assert.Equal(t, qty(80, "kg"), healthMetric.Weight)
+	assert.Equal(t, "only candidate", healthMetric.WeightReason)
+}
+
+func TestParserService_ParseClinicalNote_ReportsSpecimenDateInDateOrder(t *testing.T) {
+	testService, err := service.NewParserService(testsupport.Logger(), service.Config{DateOrder: service.DateOrderMonthFirst}, service.DefaultExtractorRegistry())
+	require.NoError(t, err)
+	healthMetric, err := testService.ParseClinicalNote(&model.ClinicalNote{Text: "HbA1c 48 mmol/mol (03/12/2025)"})
+
+	require.NoError(t, err)
+	require.Len(t, healthMetric.Labs, 1)
+	assert.Equal(t, "2025-03-12", healthMetric.Labs[0].SpecimenDate)
+}
+
+func TestNewParserService_RejectsUnknownDateOrder(t *testing.T) {
+	_, err := service.NewParserService(testsupport.Logger(), service.Config{DateOrder: "ymd"}, service.DefaultExtractorRegistry())
+
+	assert.EqualError(t, err, "date order ymd is not dmy or mdy in parser config")
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/port"
+)
+
+// ExtractorRegistry holds the metric extractors available to ParserService, in the order they run.
+type ExtractorRegistry struct {
+	extractors []port.MetricExtractor
+}
+
+func NewExtractorRegistry() *ExtractorRegistry {
+	return &ExtractorRegistry{}
+}
+
+// DefaultExtractorRegistry returns a registry with every built-in extractor registered, using the built-in
+// lexicons.
+func DefaultExtractorRegistry() *ExtractorRegistry {
+	return LexiconExtractorRegistry(DefaultLexicons())
+}
+
+// LexiconExtractorRegistry returns a registry with every built-in extractor registered, matching weight and
+// height with the keywords and unit spellings of the lexicon for the language of each note. The other
+// extractors read English only.
+func LexiconExtractorRegistry(lexicons Lexicons) *ExtractorRegistry {
+	registry := NewExtractorRegistry()
+	for _, extractor := range []port.MetricExtractor{
+		lexiconExtractor{name: model.KindWeight, lexicons: lexicons, extract: (*Lexicon).extractWeightMetrics},
+		lexiconExtractor{name: model.KindBirthWeight, lexicons: lexicons, extract: (*Lexicon).extractBirthWeightMetrics},
+		extractorFunc{name: model.KindWeightChange, extract: extractWeightChangeMetrics},
+		lexiconExtractor{name: model.KindHeight, lexicons: lexicons, extract: (*Lexicon).extractHeightMetrics},
+		extractorFunc{name: model.KindBMI, extract: extractBMIMetrics},
+		extractorFunc{name: "blood_pressure", extract: extractBloodPressureMetrics},
+		extractorFunc{name: model.KindHeartRate, extract: extractHeartRateMetrics},
+		extractorFunc{name: model.KindRespiratoryRate, extract: extractRespiratoryRateMetrics},
+		extractorFunc{name: model.KindOxygenSaturation, extract: extractOxygenSaturationMetrics},
+		extractorFunc{name: model.KindTemperature, extract: extractTemperatureMetrics},
+		extractorFunc{name: "labs", extract: extractLabMetrics},
+		extractorFunc{name: "medications", extract: extractMedicationDoseMetrics},
+	} {
+		// built-in names are unique, so registration cannot fail
+		_ = registry.Register(extractor)
+	}
+
+	return registry
+}
+
+// Register adds an extractor to the end of the registry.
+func (r *ExtractorRegistry) Register(extractor port.MetricExtractor) error {
+	if r.lookup(extractor.Name()) != nil {
+		return fmt.Errorf("extractor %s is already registered", extractor.Name())
+	}
+	r.extractors = append(r.extractors, extractor)
+
+	return nil
+}
+
+// Enabled returns the registered extractors the config allows, in registration order. Naming an extractor
+// that is not registered is an error so that a typo in deployment config is not silently ignored.
+func (r *ExtractorRegistry) Enabled(config Config) ([]port.MetricExtractor, error) {
+	for _, name := range slices.Concat(config.EnabledExtractors, config.DisabledExtractors) {
+		if r.lookup(name) == nil {
+			return nil, fmt.Errorf("unknown extractor %s in parser config", name)
+		}
+	}
+
+	var enabled []port.MetricExtractor
+	for _, extractor := range r.extractors {
+		if len(config.EnabledExtractors) > 0 && !slices.Contains(config.EnabledExtractors, extractor.Name()) {
+			continue
+		}
+		if slices.Contains(config.DisabledExtractors, extractor.Name()) {
+			continue
+		}
+		enabled = append(enabled, extractor)
+	}
+
+	return enabled, nil
+}
+
+func (r *ExtractorRegistry) lookup(name string) port.MetricExtractor {
+	for _, extractor := range r.extractors {
+		if extractor.Name() == name {
+			return extractor
+		}
+	}
+
+	return nil
+}
+
+// extractorFunc adapts one of the built-in extract functions to port.MetricExtractor.
+type extractorFunc struct {
+	name    string
+	extract func(text string) ([]model.Observation, error)
+}
+
+func (e extractorFunc) Name() string {
+	return e.name
+}
+
+func (e extractorFunc) Extract(text string) ([]model.Observation, error) {
+	return e.extract(text)
+}
+
+// lexiconExtractor adapts one of the lexicon extract methods to port.LocalizedMetricExtractor.
+type lexiconExtractor struct {
+	name     string
+	lexicons Lexicons
+	extract  func(l *Lexicon, text string) ([]model.Observation, error)
+}
+
+func (e lexiconExtractor) Name() string {
+	return e.name
+}
+
+func (e lexiconExtractor) Extract(text string) ([]model.Observation, error) {
+	return e.ExtractLanguage(text, model.LanguageEnglish)
+}
+
+// ExtractLanguage runs the lexicon of the language and then the English one, so that English keywords such as
+// "wt" are still read in a note detected as another language. An English match overlapping one already found is
+// dropped.
+func (e lexiconExtractor) ExtractLanguage(text, language string) ([]model.Observation, error) {
+	lexicon := e.lexicons.lookup(language)
+	observations, err := e.extract(lexicon, text)
+	if err != nil {
+		return nil, err
+	}
+	english := e.lexicons.lookup(model.LanguageEnglish)
+	if english == nil || english == lexicon {
+		return observations, nil
+	}
+	fallback, err := e.extract(english, text)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range fallback {
+		if !overlapsObservation(observations, o) {
+			observations = append(observations, o)
+		}
+	}
+	sort.SliceStable(observations, func(i, j int) bool { return observations[i].Start < observations[j].Start })
+
+	return observations, nil
+}
+
+func overlapsObservation(observations []model.Observation, o model.Observation) bool {
+	for _, other := range observations {
+		if o.Start < other.End && other.Start < o.End {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/port"
+	"cleo.com/internal/core/port/mocks"
+	"cleo.com/internal/core/service"
+	"cleo.com/testsupport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractorRegistry_Enabled(t *testing.T) {
+	allExtractors := []string{"weight", "birth_weight", "weight_change", "height", "bmi", "blood_pressure", "heart_rate", "respiratory_rate", "oxygen_saturation", "temperature", "labs", "medications"}
+
+	tests := []struct {
+		desc          string
+		config        service.Config
+		expectedNames []string
+		expectedError error
+	}{
+		{
+			desc:          "every extractor is enabled by default",
+			config:        service.Config{},
+			expectedNames: allExtractors,
+		},
+		{
+			desc:          "enabled list keeps registration order",
+			config:        service.Config{EnabledExtractors: []string{"height", "weight"}},
+			expectedNames: []string{"weight", "height"},
+		},
+		{
+			desc:          "disabled extractors are removed",
+			config:        service.Config{DisabledExtractors: []string{"bmi", "temperature"}},
+			expectedNames: []string{"weight", "birth_weight", "weight_change", "height", "blood_pressure", "heart_rate", "respiratory_rate", "oxygen_saturation", "labs", "medications"},
+		},
+		{
+			desc:          "unknown enabled extractor is rejected",
+			config:        service.Config{EnabledExtractors: []string{"wieght"}},
+			expectedError: errors.New("unknown extractor wieght in parser config"),
+		},
+		{
+			desc:          "unknown disabled extractor is rejected",
+			config:        service.Config{DisabledExtractors: []string{"ketones"}},
+			expectedError: errors.New("unknown extractor ketones in parser config"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			extractors, err := service.DefaultExtractorRegistry().Enabled(tt.config)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedNames, extractorNames(extractors))
+		})
+	}
+}
+
+func TestExtractorRegistry_Register(t *testing.T) {
+	registry := service.NewExtractorRegistry()
+	extractor := &mocks.MetricExtractorMock{NameFunc: func() string { return "glucose" }}
+
+	require.NoError(t, registry.Register(extractor))
+	assert.Equal(t, errors.New("extractor glucose is already registered"), registry.Register(extractor))
+}
+
+func TestParserService_ParseClinicalNote_RunsRegisteredExtractors(t *testing.T) {
+	glucose := model.Observation{Kind: "glucose", Text: "glucose 5.4", Value: 5.4, Unit: "mmol/L", End: 11, RuneEnd: 11}
+	extractor := &mocks.MetricExtractorMock{
+		NameFunc: func() string { return "glucose" },
+		ExtractFunc: func(text string) ([]model.Observation, error) {
+			return []model.Observation{glucose}, nil
+		},
+	}
+	registry := service.DefaultExtractorRegistry()
+	require.NoError(t, registry.Register(extractor))
+
+	testService, err := service.NewParserService(testsupport.Logger(), service.Config{EnabledExtractors: []string{"glucose"}}, registry)
+	require.NoError(t, err)
+
+	healthMetric, err := testService.ParseClinicalNote(&model.ClinicalNote{Text: "glucose 5.4, weight 80kg"})
+	require.NoError(t, err)
+
+	assert.Empty(t, healthMetric.Weight)
+	require.Len(t, healthMetric.Observations, 1)
+	assert.Equal(t, "glucose", healthMetric.Observations[0].Kind)
+	require.Len(t, extractor.ExtractCalls(), 1)
+	assert.Equal(t, "glucose 5.4, weight 80kg", extractor.ExtractCalls()[0].Text)
+}
+
+func TestParserService_ParseClinicalNote_ReturnsExtractorError(t *testing.T) {
+	registry := service.NewExtractorRegistry()
+	require.NoError(t, registry.Register(&mocks.MetricExtractorMock{
+		NameFunc: func() string { return "glucose" },
+		ExtractFunc: func(text string) ([]model.Observation, error) {
+			return nil, errors.New("invalid glucose")
+		},
+	}))
+
+	testService, err := service.NewParserService(testsupport.Logger(), service.Config{}, registry)
+	require.NoError(t, err)
+
+	healthMetric, err := testService.ParseClinicalNote(&model.ClinicalNote{Text: "glucose 99"})
+	assert.Nil(t, healthMetric)
+	assert.Equal(t, errors.New("invalid glucose"), err)
+}
+
+func extractorNames(extractors []port.MetricExtractor) []string {
+	var names []string
+	for _, extractor := range extractors {
+		names = append(names, extractor.Name())
+	}
+
+	return names
+}
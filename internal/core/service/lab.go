@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+// analyte describes a laboratory value: how notes name it, its SI and conventional units, how to convert between
+// them and the range a result in SI units is validated against.
+type analyte struct {
+	kind         string
+	name         string
+	keywords     string
+	si           string
+	conventional string
+	convert      func(q quantity.Quantity, code string) (quantity.Quantity, error)
+	// a value written without a unit is read in belowUnit under unitThreshold and in aboveUnit from it on
+	unitThreshold float64
+	belowUnit     string
+	aboveUnit     string
+	plausible     [2]float64
+	// siPrecision and conventionalPrecision are the decimal places each unit is reported to
+	siPrecision           int
+	conventionalPrecision int
+}
+
+// analytes are listed in the order their results are reported. Molar masses are in g/mol.
+var analytes = []analyte{
+	{kind: model.KindHbA1c, name: "HbA1c", keywords: `hba1c|hb\s*a1c|a1c|glycated\s+ha?emoglobin`,
+		si: "mmol/mol", conventional: "%", convert: convertHbA1c,
+		unitThreshold: 20, belowUnit: "%", aboveUnit: "mmol/mol",
+		plausible: [2]float64{15, 200}, siPrecision: 0, conventionalPrecision: 1},
+	{kind: model.KindGlucose, name: "glucose", keywords: `(?:(?:fasting|random|plasma|blood|capillary)\s+)*glucose|cbg|fbg|fpg|bm`,
+		si: "mmol/L", conventional: "mg/dL", convert: molar(180.156),
+		unitThreshold: 60, belowUnit: "mmol/L", aboveUnit: "mg/dL",
+		plausible: [2]float64{1, 60}, siPrecision: 1, conventionalPrecision: 0},
+	{kind: model.KindTotalCholesterol, name: "total cholesterol", keywords: `(?:total\s+|serum\s+)?cholesterol|t\.?\s*chol`,
+		si: "mmol/L", conventional: "mg/dL", convert: molar(386.65),
+		unitThreshold: 20, belowUnit: "mmol/L", aboveUnit: "mg/dL",
+		plausible: [2]float64{1, 20}, siPrecision: 1, conventionalPrecision: 0},
+	{kind: model.KindLDLCholesterol, name: "LDL cholesterol", keywords: `ldl(?:[\s-]*(?:cholesterol|chol|c)\b)?`,
+		si: "mmol/L", conventional: "mg/dL", convert: molar(386.65),
+		unitThreshold: 20, belowUnit: "mmol/L", aboveUnit: "mg/dL",
+		plausible: [2]float64{0.1, 15}, siPrecision: 1, conventionalPrecision: 0},
+	{kind: model.KindCreatinine, name: "creatinine", keywords: `creatinine|creat|cr`,
+		si: "umol/L", conventional: "mg/dL", convert: molar(113.12),
+		unitThreshold: 20, belowUnit: "mg/dL", aboveUnit: "umol/L",
+		plausible: [2]float64{10, 2000}, siPrecision: 0, conventionalPrecision: 2},
+	{kind: model.KindEGFR, name: "eGFR", keywords: `egfr`,
+		si: "mL/min/{1.73_m2}", conventional: "mL/min/{1.73_m2}", convert: quantity.Quantity.To,
+		plausible: [2]float64{1, 200}, siPrecision: 0, conventionalPrecision: 0},
+}
+
+const (
+	// labUnitsPattern lists the units of every analyte, longest first where one begins another.
+	labUnitsPattern = `mmol/mol|mmol/l|mg/dl|[uµμ]mol/l|micromol/l|ml/min(?:/1\.73\s*m(?:2|²))?|%`
+	// specimenDatePattern takes a sample date after the value into the mention, "(12/03/2025)", "on 2025-03-12"
+	// or "dated 12/03/2025", so that it is the date the result is attached to.
+	specimenDatePattern = `(?:\s*(?:\(\s*|on\s+|dated\s+)(?:\d{4}-\d{2}-\d{2}|\d{1,2}/\d{1,2}/\d{2,4})\s*\)?)?`
+)
+
+var (
+	// labRegex captures HDL cholesterol first, so that it is skipped rather than read as total cholesterol, then
+	// the keyword of each analyte in its own group, the value and the unit.
+	labRegex = compileLabRegex()
+	// the groups after the analyte keywords
+	labValueGroup = len(analytes) + 2
+	labUnitGroup  = labValueGroup + 1
+)
+
+func compileLabRegex() *regexp.Regexp {
+	keywords := []string{`(hdl(?:[\s-]*(?:cholesterol|chol|c)\b)?)`}
+	for _, a := range analytes {
+		keywords = append(keywords, `(`+a.keywords+`)`)
+	}
+
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(keywords, "|") + `)\s*(?:of|is|was|at|:|=)?\s*` +
+		`(\d{1,4}(?:\.\d{1,2})?)\s*(` + labUnitsPattern + `)?` + specimenDatePattern)
+}
+
+// extractLabMetrics returns every laboratory result in the note in the SI unit of its analyte, in order of
+// appearance. A result in a unit that does not fit its analyte is skipped, and one outside the plausible range
+// is marked implausible rather than failing the note.
+func extractLabMetrics(text string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, m := range labRegex.FindAllStringSubmatchIndex(text, -1) {
+		a, ok := matchedAnalyte(m)
+		if !ok {
+			continue
+		}
+		valStr := text[m[2*labValueGroup]:m[2*labValueGroup+1]]
+		v, err := strconv.ParseFloat(valStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given %s of %s ", a.name, valStr)
+		}
+		unitStr := ""
+		if m[2*labUnitGroup] >= 0 {
+			unitStr = text[m[2*labUnitGroup]:m[2*labUnitGroup+1]]
+		}
+		written, err := a.written(v, unitStr)
+		if errors.Is(err, quantity.ErrUnknownUnit) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given %s of %s: %w", a.name, text[m[0]:m[1]], err)
+		}
+		si, err := a.convert(written, a.si)
+		if errors.Is(err, quantity.ErrIncompatibleUnits) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given %s of %s: %w", a.name, text[m[0]:m[1]], err)
+		}
+		si = si.Round(a.siPrecision)
+		observation := newObservation(text, m[0], m[1], a.kind, si)
+		observation.Written = &written
+		observation.Implausible = si.Value < a.plausible[0] || si.Value > a.plausible[1]
+		observations = append(observations, observation)
+	}
+
+	return observations, nil
+}
+
+// matchedAnalyte returns the analyte whose keyword group took part in the match, or false for HDL.
+func matchedAnalyte(m []int) (analyte, bool) {
+	for i, a := range analytes {
+		if m[2*(i+2)] >= 0 {
+			return a, true
+		}
+	}
+
+	return analyte{}, false
+}
+
+// written returns the value in the unit the note gives, or the one its magnitude implies when no unit is given.
+func (a analyte) written(v float64, unitStr string) (quantity.Quantity, error) {
+	switch {
+	case a.si == a.conventional:
+		return quantity.Quantity{Value: v, Unit: a.si}, nil
+	case unitStr == "" && v < a.unitThreshold:
+		return quantity.Quantity{Value: v, Unit: a.belowUnit}, nil
+	case unitStr == "":
+		return quantity.Quantity{Value: v, Unit: a.aboveUnit}, nil
+	}
+	unit, err := quantity.Parse(unitStr)
+	if err != nil {
+		return quantity.Quantity{}, err
+	}
+
+	return quantity.Quantity{Value: v, Unit: unit.Code}, nil
+}
+
+// molar converts between the mass and substance concentrations of an analyte with the given molar mass.
+func molar(molarMass float64) func(q quantity.Quantity, code string) (quantity.Quantity, error) {
+	return func(q quantity.Quantity, code string) (quantity.Quantity, error) {
+		return q.ToAnalyte(code, molarMass)
+	}
+}
+
+// convertHbA1c converts between the NGSP percentage and IFCC mmol/mol with the master equation
+// NGSP = 0.09148 × IFCC + 2.152.
+func convertHbA1c(q quantity.Quantity, code string) (quantity.Quantity, error) {
+	switch {
+	case q.Unit == code:
+		return q, nil
+	case q.Unit == "%" && code == "mmol/mol":
+		return quantity.New((q.Value-2.152)/0.09148, code)
+	case q.Unit == "mmol/mol" && code == "%":
+		return quantity.New(0.09148*q.Value+2.152, code)
+	}
+
+	return quantity.Quantity{}, fmt.Errorf("%w: %s to %s", quantity.ErrIncompatibleUnits, q.Unit, code)
+}
+
+// specimenDate returns the date the result is attached to as YYYY-MM-DD, or empty when it has none.
+func specimenDate(o model.Observation) string {
+	if o.EffectiveTime == nil {
+		return ""
+	}
+
+	return o.EffectiveTime.Format(time.DateOnly)
+}
+
+func lookupAnalyte(kind string) (analyte, bool) {
+	for _, a := range analytes {
+		if a.kind == kind {
+			return a, true
+		}
+	}
+
+	return analyte{}, false
+}
+
+// selectLabs reports the primary result of each analyte, as written and in SI and conventional units.
+func selectLabs(observations []model.Observation) ([]model.LabResult, error) {
+	var labs []model.LabResult
+	for _, a := range analytes {
+		o, _ := selectPrimary(observations, a.kind)
+		if o == nil {
+			continue
+		}
+		written := o.Quantity()
+		if o.Written != nil {
+			written = *o.Written
+		}
+		conventional, err := a.convert(written, a.conventional)
+		if err != nil {
+			return nil, err
+		}
+		labs = append(labs, model.LabResult{
+			Analyte:      a.kind,
+			Value:        written.Value,
+			Unit:         written.Unit,
+			SI:           o.Quantity(),
+			Conventional: conventional.Round(a.conventionalPrecision),
+			SpecimenDate: specimenDate(*o),
+		})
+	}
+
+	return labs, nil
+}
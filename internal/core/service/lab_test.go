@@ -0,0 +1,119 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForLabs(t *testing.T) {
+	tests := []struct {
+		desc           string
+		clinicalNote   *model.ClinicalNote
+		expectedLabs   []model.LabResult
+		expectedReview int
+	}{
+		{
+			desc:         "HbA1c in mmol/mol",
+			clinicalNote: &model.ClinicalNote{Text: "HbA1c 48 mmol/mol"},
+			expectedLabs: []model.LabResult{{Analyte: model.KindHbA1c, Value: 48, Unit: "mmol/mol",
+				SI: quantity.Quantity{Value: 48, Unit: "mmol/mol"}, Conventional: quantity.Quantity{Value: 6.5, Unit: "%"}}},
+		},
+		{
+			desc:         "HbA1c as a percentage",
+			clinicalNote: &model.ClinicalNote{Text: "HbA1c 6.5%"},
+			expectedLabs: []model.LabResult{{Analyte: model.KindHbA1c, Value: 6.5, Unit: "%",
+				SI: quantity.Quantity{Value: 48, Unit: "mmol/mol"}, Conventional: quantity.Quantity{Value: 6.5, Unit: "%"}}},
+		},
+		{
+			desc:         "fasting glucose in mmol/L",
+			clinicalNote: &model.ClinicalNote{Text: "fasting glucose 5.4 mmol/L"},
+			expectedLabs: []model.LabResult{{Analyte: model.KindGlucose, Value: 5.4, Unit: "mmol/L",
+				SI: quantity.Quantity{Value: 5.4, Unit: "mmol/L"}, Conventional: quantity.Quantity{Value: 97, Unit: "mg/dL"}}},
+		},
+		{
+			desc:         "glucose in mg/dL",
+			clinicalNote: &model.ClinicalNote{Text: "glucose 126 mg/dL"},
+			expectedLabs: []model.LabResult{{Analyte: model.KindGlucose, Value: 126, Unit: "mg/dL",
+				SI: quantity.Quantity{Value: 7, Unit: "mmol/L"}, Conventional: quantity.Quantity{Value: 126, Unit: "mg/dL"}}},
+		},
+		{
+			desc:         "total cholesterol with a specimen date",
+			clinicalNote: &model.ClinicalNote{Text: "total cholesterol 200 mg/dL (12/03/2025)"},
+			expectedLabs: []model.LabResult{{Analyte: model.KindTotalCholesterol, Value: 200, Unit: "mg/dL",
+				SI: quantity.Quantity{Value: 5.2, Unit: "mmol/L"}, Conventional: quantity.Quantity{Value: 200, Unit: "mg/dL"},
+				SpecimenDate: "2025-03-12"}},
+		},
+		{
+			desc:         "LDL read and HDL skipped",
+			clinicalNote: &model.ClinicalNote{Text: "LDL 2.6 mmol/L, HDL cholesterol 1.2 mmol/L"},
+			expectedLabs: []model.LabResult{{Analyte: model.KindLDLCholesterol, Value: 2.6, Unit: "mmol/L",
+				SI: quantity.Quantity{Value: 2.6, Unit: "mmol/L"}, Conventional: quantity.Quantity{Value: 101, Unit: "mg/dL"}}},
+		},
+		{
+			desc:         "creatinine in mg/dL",
+			clinicalNote: &model.ClinicalNote{Text: "creatinine 1.2 mg/dL"},
+			expectedLabs: []model.LabResult{{Analyte: model.KindCreatinine, Value: 1.2, Unit: "mg/dL",
+				SI: quantity.Quantity{Value: 106, Unit: "umol/L"}, Conventional: quantity.Quantity{Value: 1.2, Unit: "mg/dL"}}},
+		},
+		{
+			desc:         "unitless creatinine read in umol/L by magnitude",
+			clinicalNote: &model.ClinicalNote{Text: "Cr 85"},
+			expectedLabs: []model.LabResult{{Analyte: model.KindCreatinine, Value: 85, Unit: "umol/L",
+				SI: quantity.Quantity{Value: 85, Unit: "umol/L"}, Conventional: quantity.Quantity{Value: 0.96, Unit: "mg/dL"}}},
+		},
+		{
+			desc:         "eGFR with an ISO specimen date",
+			clinicalNote: &model.ClinicalNote{Text: "eGFR 72 mL/min/1.73m2 on 2025-03-12"},
+			expectedLabs: []model.LabResult{{Analyte: model.KindEGFR, Value: 72, Unit: "mL/min/{1.73_m2}",
+				SI: quantity.Quantity{Value: 72, Unit: "mL/min/{1.73_m2}"}, Conventional: quantity.Quantity{Value: 72, Unit: "mL/min/{1.73_m2}"},
+				SpecimenDate: "2025-03-12"}},
+		},
+		{
+			desc:         "results reported in analyte order",
+			clinicalNote: &model.ClinicalNote{Text: "eGFR 90, HbA1c 53 mmol/mol"},
+			expectedLabs: []model.LabResult{
+				{Analyte: model.KindHbA1c, Value: 53, Unit: "mmol/mol",
+					SI: quantity.Quantity{Value: 53, Unit: "mmol/mol"}, Conventional: quantity.Quantity{Value: 7, Unit: "%"}},
+				{Analyte: model.KindEGFR, Value: 90, Unit: "mL/min/{1.73_m2}",
+					SI: quantity.Quantity{Value: 90, Unit: "mL/min/{1.73_m2}"}, Conventional: quantity.Quantity{Value: 90, Unit: "mL/min/{1.73_m2}"}},
+			},
+		},
+		{
+			desc:           "implausible glucose held back for review",
+			clinicalNote:   &model.ClinicalNote{Text: "glucose 90 mmol/L"},
+			expectedReview: 1,
+		},
+		{
+			desc:           "implausible unitless glucose held back for review",
+			clinicalNote:   &model.ClinicalNote{Text: "glucose 0.5"},
+			expectedReview: 1,
+		},
+		{
+			desc:         "unit of the wrong analyte is skipped",
+			clinicalNote: &model.ClinicalNote{Text: "HbA1c 48 mmol/L, glucose 5.5 %, creatinine 90 ml/min"},
+		},
+		{
+			desc:         "result in the wrong unit does not hide the others",
+			clinicalNote: &model.ClinicalNote{Text: "HbA1c 6.5 mmol/L, HbA1c 48 mmol/mol"},
+			expectedLabs: []model.LabResult{{Analyte: model.KindHbA1c, Value: 48, Unit: "mmol/mol",
+				SI: quantity.Quantity{Value: 48, Unit: "mmol/mol"}, Conventional: quantity.Quantity{Value: 6.5, Unit: "%"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedLabs, healthMetric.Labs)
+			assert.Len(t, healthMetric.NeedsReview, tt.expectedReview)
+		})
+	}
+}
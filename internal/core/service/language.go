@@ -0,0 +1,129 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"cleo.com/internal/core/domain/model"
+)
+
+// language describes how a language writes numbers and the common words that identify a note written in it.
+type language struct {
+	// decimalComma marks a language that writes 75,5 for 75.5 and groups thousands with a point or a
+	// non-breaking space.
+	decimalComma bool
+	// markers weighs each word by how strongly it suggests the language.
+	markers map[string]int
+}
+
+// languages are tried in this order, so that a tie in detection goes to the earlier one.
+var languageOrder = []string{model.LanguageEnglish, model.LanguageGerman, model.LanguageFrench, model.LanguageSpanish, model.LanguageDutch}
+
+// The markers are common words that are rare in the other supported languages, so "de", "la", and words that
+// English notes also use such as "met", "en", "van", "est", "et" and "es", are left out. The names of the metrics
+// come second and count double, so that "Gewicht 80 kg" alone is read as German. A name two languages share, such
+// as "gewicht", counts for both, so that the words only one of them uses decide between them.
+var languages = map[string]language{
+	model.LanguageEnglish: {markers: markerSet("the and with of was is", "weight height weighs")},
+	model.LanguageGerman: {decimalComma: true,
+		markers: markerSet("und mit der die das ist nicht bei", "gewicht größe groesse körpergröße geburtsgewicht")},
+	model.LanguageFrench: {decimalComma: true,
+		markers: markerSet("le les avec du des une pas", "poids taille naissance")},
+	model.LanguageSpanish: {decimalComma: true,
+		markers: markerSet("y el los las con del una", "peso talla estatura altura nacer")},
+	model.LanguageDutch: {decimalComma: true,
+		markers: markerSet("het een niet", "gewicht lengte geboortegewicht gewogen")},
+}
+
+const (
+	// minMarkers and markerMargin are the marker weight another language needs in the note, in all and over
+	// English, before the note is read in it rather than in English.
+	minMarkers   = 2
+	markerMargin = 2
+)
+
+var (
+	// localeRegex reads the language from a hint such as "de", "de-AT" or "fr_CH".
+	localeRegex = regexp.MustCompile(`^([a-zA-Z]{2})(?:[-_][a-zA-Z]{2})?$`)
+	letterRegex = regexp.MustCompile(`\p{L}+`)
+)
+
+func markerSet(words, metricWords string) map[string]int {
+	set := map[string]int{}
+	for _, word := range strings.Fields(words) {
+		set[word] = 1
+	}
+	for _, word := range strings.Fields(metricWords) {
+		set[word] = 2
+	}
+
+	return set
+}
+
+// resolveLanguage returns the language of the hint on the request, or failing that the supported language with
+// the most marker words in the note, defaulting to English unless another language has enough of them.
+func resolveLanguage(hint, text string) string {
+	if m := localeRegex.FindStringSubmatch(hint); m != nil {
+		if _, ok := languages[strings.ToLower(m[1])]; ok {
+			return strings.ToLower(m[1])
+		}
+	}
+
+	counts := map[string]int{}
+	for _, word := range letterRegex.FindAllString(strings.ToLower(text), -1) {
+		for name, l := range languages {
+			counts[name] += l.markers[word]
+		}
+	}
+	detected := model.LanguageEnglish
+	for _, name := range languageOrder {
+		if counts[name] > counts[detected] {
+			detected = name
+		}
+	}
+	if counts[detected] < minMarkers || counts[detected] < counts[model.LanguageEnglish]+markerMargin {
+		return model.LanguageEnglish
+	}
+
+	return detected
+}
+
+var (
+	// decimalPointRegex matches a number with commas or non-breaking spaces between its thousands, as English
+	// writes 1,200, or, captured, a decimal comma that cannot be a thousands separator because one or two digits
+	// follow it before a unit, as in 75,5 kg or 37,5°C.
+	decimalPointRegex = regexp.MustCompile(`\b\d{1,3}(?:[,\x{00A0}\x{202F}]\d{3})+(?:\.\d+)?\b|\b(\d{1,4},\d{1,2})\s*[\p{L}%°]`)
+	// decimalCommaRegex matches a number written with a decimal comma, a point or non-breaking space between
+	// its thousands, or both: 75,5, 3.400 and 1 250,5.
+	decimalCommaRegex = regexp.MustCompile(`\b\d{1,3}(?:[.\x{00A0}\x{202F}]\d{3})+(?:,\d+)?\b|\b\d+,\d+\b`)
+)
+
+// normalizeNumberFormat rewrites numbers in the form the language writes them as plain digits with a decimal
+// point, e.g. "Gewicht 75,5 kg" as "Gewicht 75.5 kg" and "3.400 g" as "3400 g". A decimal comma before a unit is
+// also read in English notes.
+func normalizeNumberFormat(text, name string) normalizedText {
+	b := newNormalizer(text)
+	numberRegex, decimal := decimalPointRegex, "."
+	if languages[name].decimalComma {
+		numberRegex, decimal = decimalCommaRegex, ","
+	}
+	for _, m := range numberRegex.FindAllStringSubmatchIndex(text, -1) {
+		start, end, separator := m[0], m[1], decimal
+		if len(m) > 2 && m[2] >= 0 {
+			start, end, separator = m[2], m[3], ","
+		}
+		whole, fraction, _ := strings.Cut(text[start:end], separator)
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, whole)
+		if fraction != "" {
+			digits += "." + fraction
+		}
+		b.replace(start, end, digits)
+	}
+
+	return b.done()
+}
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForLanguages(t *testing.T) {
+	tests := []struct {
+		desc             string
+		clinicalNote     *model.ClinicalNote
+		expectedLanguage string
+		expectedWeight   *quantity.Quantity
+		expectedHeight   *quantity.Quantity
+	}{
+		{
+			desc:             "German detected, with decimal commas",
+			clinicalNote:     &model.ClinicalNote{Text: "Gewicht 75,5 kg, Größe 1,80 m"},
+			expectedLanguage: model.LanguageGerman,
+			expectedWeight:   qty(75.5, "kg"),
+			expectedHeight:   qty(180, "cm"),
+		},
+		{
+			desc:             "French detected",
+			clinicalNote:     &model.ClinicalNote{Text: "poids : 80 kg, taille 1,80 m"},
+			expectedLanguage: model.LanguageFrench,
+			expectedWeight:   qty(80, "kg"),
+			expectedHeight:   qty(180, "cm"),
+		},
+		{
+			desc:             "Spanish detected",
+			clinicalNote:     &model.ClinicalNote{Text: "peso 72,3 kilos, talla 165 cm"},
+			expectedLanguage: model.LanguageSpanish,
+			expectedWeight:   qty(72.3, "kg"),
+			expectedHeight:   qty(165, "cm"),
+		},
+		{
+			desc:             "Dutch hinted, with a point between thousands",
+			clinicalNote:     &model.ClinicalNote{Text: "gewicht 3.400 gram, lengte 52 cm", Language: "nl"},
+			expectedLanguage: model.LanguageDutch,
+			expectedWeight:   qty(3.4, "kg"),
+			expectedHeight:   qty(52, "cm"),
+		},
+		{
+			desc:             "Dutch detected with a capitalised weight shared with German",
+			clinicalNote:     &model.ClinicalNote{Text: "Gewicht 80,5 kg, lengte 1,80 m"},
+			expectedLanguage: model.LanguageDutch,
+			expectedWeight:   qty(80.5, "kg"),
+			expectedHeight:   qty(180, "cm"),
+		},
+		{
+			desc:             "German detected from the shared weight alone",
+			clinicalNote:     &model.ClinicalNote{Text: "Gewicht 80 kg"},
+			expectedLanguage: model.LanguageGerman,
+			expectedWeight:   qty(80, "kg"),
+		},
+		{
+			desc:             "locale hint is read as its language",
+			clinicalNote:     &model.ClinicalNote{Text: "Gewicht 80 kg", Language: "de-AT"},
+			expectedLanguage: model.LanguageGerman,
+			expectedWeight:   qty(80, "kg"),
+		},
+		{
+			desc:             "hint overrides detection",
+			clinicalNote:     &model.ClinicalNote{Text: "weight and height: poids 3.400 g", Language: "fr"},
+			expectedLanguage: model.LanguageFrench,
+			expectedWeight:   qty(3.4, "kg"),
+		},
+		{
+			desc:             "English detected, with commas between thousands",
+			clinicalNote:     &model.ClinicalNote{Text: "the weight was 1,200 g"},
+			expectedLanguage: model.LanguageEnglish,
+			expectedWeight:   qty(1.2, "kg"),
+		},
+		{
+			desc:             "Dutch word used in English is not a marker",
+			clinicalNote:     &model.ClinicalNote{Text: "wt 80kg, sepsis criteria met"},
+			expectedLanguage: model.LanguageEnglish,
+			expectedWeight:   qty(80, "kg"),
+		},
+		{
+			desc:             "abbreviated English note stays English",
+			clinicalNote:     &model.ClinicalNote{Text: "Wt 80kg. Ht 175cm. Pt stable, obs met targets"},
+			expectedLanguage: model.LanguageEnglish,
+			expectedWeight:   qty(80, "kg"),
+			expectedHeight:   qty(175, "cm"),
+		},
+		{
+			desc:             "French word used in English is not a marker",
+			clinicalNote:     &model.ClinicalNote{Text: "est wt 80kg"},
+			expectedLanguage: model.LanguageEnglish,
+			expectedWeight:   qty(80, "kg"),
+		},
+		{
+			desc:             "English keywords are read in a note in another language",
+			clinicalNote:     &model.ClinicalNote{Text: "Größe 180 cm, wt 80 kg", Language: "de"},
+			expectedLanguage: model.LanguageGerman,
+			expectedWeight:   qty(80, "kg"),
+			expectedHeight:   qty(180, "cm"),
+		},
+		{
+			desc:             "English by default",
+			clinicalNote:     &model.ClinicalNote{Text: "wt 80 kg"},
+			expectedLanguage: model.LanguageEnglish,
+			expectedWeight:   qty(80, "kg"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedLanguage, healthMetric.Language)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			assert.Equal(t, tt.expectedHeight, healthMetric.Height)
+		})
+	}
+}
+
+func TestParserService_ParseClinicalNote_ReportsOriginalSpanOfLocalizedNumber(t *testing.T) {
+	testService := newTestParserService(t)
+	healthMetric, err := testService.ParseClinicalNote(&model.ClinicalNote{Text: "Geburtsgewicht 3.400 g", AgeDays: func(d int) *int { return &d }(2)})
+
+	require.NoError(t, err)
+	require.Len(t, healthMetric.Observations, 1)
+	assert.Equal(t, "Geburtsgewicht 3.400 g", healthMetric.Observations[0].Text)
+	assert.Equal(t, qty(3.4, "kg"), healthMetric.BirthWeight)
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+//go:embed lexicons/*.json
+var defaultLexicons embed.FS
+
+// Lexicon is a site's vocabulary for weight and height in one language: the keywords that introduce a value and
+// the spellings of the units that may follow it. Compound imperial forms such as 12 st 4 lb and 5'9" are
+// recognised whatever the lexicon says.
+type Lexicon struct {
+	// Language is the language the lexicon is for, defaulting to English.
+	Language string       `json:"language,omitempty"`
+	Weight   LexiconEntry `json:"weight"`
+	// BirthWeight takes its units from Weight.
+	BirthWeight LexiconEntry `json:"birth_weight"`
+	Height      LexiconEntry `json:"height"`
+
+	weightRegex *regexp.Regexp
+	heightRegex *regexp.Regexp
+	// spellings maps each unit spelling, lower-cased with spaces removed, to its UCUM code.
+	spellings map[string]string
+}
+
+type LexiconEntry struct {
+	Keywords []string `json:"keywords"`
+	// Units maps UCUM codes to the ways the unit is spelled after a value.
+	Units map[string][]string `json:"units,omitempty"`
+}
+
+// The parts of the weight and height patterns that do not come from the lexicon.
+const (
+	connectorPattern     = `\s*(?:of|is|was|at|:|=|(?:was\s+)?found\s+to\s+be|recorded\s+as)?\s*`
+	approximationPattern = `(?:(approx(?:imately|\.)?|about|around|roughly|circa|~|≈)\s*)?`
+	stonesPattern        = `(\d{1,2}(?:\.\d{1,2})?)\s*(?:stones|stone|st)(?:\s*(\d{1,2}(?:\.\d{1,2})?)(?:\s*(?:lbs|lb|pounds|pound)\b)?|\b)`
+	poundsPattern        = `(\d{1,2})\s*(?:lbs|lb|pounds|pound)\s*(\d{1,2}(?:\.\d{1,2})?)\s*(?:oz|ounces|ounce)\b`
+	feetPattern          = `(\d)\s*(?:'|′|’|feet|foot|ft)(?:\s*(\d{1,2}(?:\.\d{1,2})?)(?:\s*(?:"|″|”|''|′′|inches\b|inch\b|ins?\b))?)?`
+)
+
+// Lexicons holds the lexicon for each language a note may be read in.
+type Lexicons map[string]*Lexicon
+
+// DefaultLexicons returns the built-in vocabulary of every supported language.
+func DefaultLexicons() Lexicons {
+	lexicons := Lexicons{}
+	for _, name := range languageOrder {
+		data, err := defaultLexicons.ReadFile("lexicons/" + name + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("built-in %s lexicon is missing: %s", name, err))
+		}
+		lexicon, err := ParseLexicon(data)
+		if err != nil {
+			panic(fmt.Sprintf("built-in %s lexicon is invalid: %s", name, err))
+		}
+		lexicons[name] = lexicon
+	}
+
+	return lexicons
+}
+
+// lookup returns the lexicon for the language, falling back to English.
+func (ls Lexicons) lookup(name string) *Lexicon {
+	if lexicon, ok := ls[name]; ok {
+		return lexicon
+	}
+
+	return ls[model.LanguageEnglish]
+}
+
+// ParseLexicon reads a JSON lexicon, validates every entry and compiles the weight and height patterns from it.
+func ParseLexicon(data []byte) (*Lexicon, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	lexicon := &Lexicon{}
+	if err := decoder.Decode(lexicon); err != nil {
+		return nil, fmt.Errorf("unable to parse lexicon: %w", err)
+	}
+	if err := lexicon.compile(); err != nil {
+		return nil, fmt.Errorf("invalid lexicon: %w", err)
+	}
+
+	return lexicon, nil
+}
+
+func (l *Lexicon) compile() error {
+	if l.Language == "" {
+		l.Language = model.LanguageEnglish
+	}
+	if _, ok := languages[l.Language]; !ok {
+		return fmt.Errorf("language %s is not supported", l.Language)
+	}
+	if len(l.BirthWeight.Units) > 0 {
+		return errors.New("birth_weight units are taken from weight and cannot be given")
+	}
+	weightKeywords, err := keywordsPattern("weight", l.Weight.Keywords)
+	if err != nil {
+		return err
+	}
+	birthWeightKeywords, err := keywordsPattern("birth_weight", l.BirthWeight.Keywords)
+	if err != nil {
+		return err
+	}
+	heightKeywords, err := keywordsPattern("height", l.Height.Keywords)
+	if err != nil {
+		return err
+	}
+	l.spellings = map[string]string{}
+	weightUnits, err := l.unitsPattern("weight", l.Weight.Units, quantity.Mass)
+	if err != nil {
+		return err
+	}
+	heightUnits, err := l.unitsPattern("height", l.Height.Units, quantity.Length)
+	if err != nil {
+		return err
+	}
+
+	// weightRegex matches either stones with an optional pounds remainder (12 st 4 lb, 12st4, 12 stone 4), captured
+	// as stones and pounds, pounds with an ounces remainder (7 lb 8 oz), captured as pounds and ounces, or a single
+	// value, or range of values, with its unit. A birth weight keyword is captured first so that it is never read
+	// as the current weight, then any approximation qualifier (approx, about, ~). A person named with "is" or "was"
+	// introduces a weight like a keyword.
+	l.weightRegex, err = regexp.Compile(`(?i)\b(?:(` + birthWeightKeywords + `)|` + weightKeywords + `|` + subjectIsPattern + `)` +
+		connectorPattern + approximationPattern + `(?:` + stonesPattern + `|` + poundsPattern +
+		`|(\d{1,4}(?:\.\d{1,2})?)(?:\s*(?:-|–|to)\s*(\d{1,4}(?:\.\d{1,2})?))?\s*(` + weightUnits + `))`)
+	if err != nil {
+		return err
+	}
+	// heightRegex matches either a compound imperial height (5'9", 5′9″, 5 ft 9 in, 5ft9, 5 foot 9), captured as
+	// feet and inches, or a single value, or range of values, with its unit, after any approximation qualifier.
+	l.heightRegex, err = regexp.Compile(`(?i)\b(?:` + heightKeywords + `)` + connectorPattern + approximationPattern +
+		`(?:` + feetPattern + `|(\d{1,5}(?:\.\d{1,2})?)(?:\s*(?:-|–|to)\s*(\d{1,5}(?:\.\d{1,2})?))?\s*(` + heightUnits + `))`)
+
+	return err
+}
+
+// keywordsPattern returns an alternation of the keywords, longest first so that "Wt." is not cut short at "Wt".
+// Words within a keyword may be run together or spaced, so "birth weight" also matches "birthweight".
+func keywordsPattern(entry string, keywords []string) (string, error) {
+	if len(keywords) == 0 {
+		return "", fmt.Errorf("%s has no keywords", entry)
+	}
+	var alternatives []string
+	for i, keyword := range keywords {
+		words := strings.Fields(keyword)
+		if len(words) == 0 {
+			return "", fmt.Errorf("%s keyword %d is empty", entry, i+1)
+		}
+		for j := range words {
+			words[j] = regexp.QuoteMeta(words[j])
+		}
+		alternatives = append(alternatives, strings.Join(words, `\s*`))
+	}
+
+	return longestFirst(alternatives), nil
+}
+
+// unitsPattern records the spellings of each unit and returns an alternation of them. Every unit must be of the
+// given dimension and a spelling may only stand for one unit.
+func (l *Lexicon) unitsPattern(entry string, units map[string][]string, dimension quantity.Dimension) (string, error) {
+	if len(units) == 0 {
+		return "", fmt.Errorf("%s has no units", entry)
+	}
+	codes := make([]string, 0, len(units))
+	for code := range units {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	var alternatives []string
+	for _, code := range codes {
+		spellings := units[code]
+		unit, err := quantity.Lookup(code)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", entry, err)
+		}
+		if unit.Dimension != dimension {
+			return "", fmt.Errorf("%s unit %s is a %s, not a %s", entry, code, unit.Dimension, dimension)
+		}
+		if len(spellings) == 0 {
+			return "", fmt.Errorf("%s unit %s has no spellings", entry, code)
+		}
+		for _, spelling := range spellings {
+			key := strings.ToLower(strings.Join(strings.Fields(spelling), ""))
+			if key == "" {
+				return "", fmt.Errorf("%s unit %s has an empty spelling", entry, code)
+			}
+			if other, ok := l.spellings[key]; ok && other != code {
+				return "", fmt.Errorf("%s spelling %q is given for both %s and %s", entry, spelling, other, code)
+			}
+			l.spellings[key] = code
+			alternatives = append(alternatives, spellingPattern(spelling))
+		}
+	}
+
+	return longestFirst(alternatives), nil
+}
+
+// spellingPattern matches a unit spelling with any spacing between its words. A spelling that ends in an ASCII
+// letter or digit must end a word, so that "m" does not match the start of "mg"; \b only knows ASCII words.
+func spellingPattern(spelling string) string {
+	words := strings.Fields(spelling)
+	for i := range words {
+		words[i] = regexp.QuoteMeta(words[i])
+	}
+	pattern := strings.Join(words, `\s*`)
+	if r := []rune(strings.TrimSpace(spelling)); r[len(r)-1] < unicode.MaxASCII && (unicode.IsLetter(r[len(r)-1]) || unicode.IsDigit(r[len(r)-1])) {
+		pattern += `\b`
+	}
+
+	return pattern
+}
+
+// parseQuantity reads a value and a unit spelled as the lexicon allows.
+func (l *Lexicon) parseQuantity(valStr, unitStr string) (quantity.Quantity, error) {
+	code, ok := l.spellings[strings.ToLower(strings.Join(strings.Fields(unitStr), ""))]
+	if !ok {
+		return parseQuantity(valStr, unitStr)
+	}
+	v, err := strconv.ParseFloat(valStr, 64)
+	if err != nil {
+		return quantity.Quantity{}, err
+	}
+
+	return quantity.New(v, code)
+}
+
+func longestFirst(alternatives []string) string {
+	sort.SliceStable(alternatives, func(i, j int) bool { return len(alternatives[i]) > len(alternatives[j]) })
+	return strings.Join(alternatives, "|")
+}
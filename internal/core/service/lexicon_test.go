@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+	"cleo.com/internal/core/service"
+	"cleo.com/testsupport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const siteLexicon = `{
+	"weight": {"keywords": ["weight", "Wt."], "units": {"kg": ["kg", "kilos"], "[lb_av]": ["lb", "lbs"]}},
+	"birth_weight": {"keywords": ["birth weight"]},
+	"height": {"keywords": ["height", "Ht/Lt"], "units": {"cm": ["cm", "cms"]}}
+}`
+
+func TestParserService_ParseClinicalNote_ForSiteLexicon(t *testing.T) {
+	tests := []struct {
+		desc                string
+		clinicalNote        *model.ClinicalNote
+		expectedWeight      *quantity.Quantity
+		expectedBirthWeight *quantity.Quantity
+		expectedHeight      *quantity.Quantity
+	}{
+		{
+			desc:           "site keywords and unit spellings",
+			clinicalNote:   &model.ClinicalNote{Text: "Wt. 80 kilos, Ht/Lt 180 cms"},
+			expectedWeight: qty(80, "kg"),
+			expectedHeight: qty(180, "cm"),
+		},
+		{
+			desc:           "pounds spelled as the site allows",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 176 lbs"},
+			expectedWeight: qty(79.83, "kg"),
+		},
+		{
+			desc:                "birth weight takes the weight units",
+			clinicalNote:        &model.ClinicalNote{Text: "birth weight 3.4 kilos", AgeDays: func(d int) *int { return &d }(2)},
+			expectedBirthWeight: qty(3.4, "kg"),
+		},
+		{
+			desc:           "weight given directly for the patient whatever the keywords",
+			clinicalNote:   &model.ClinicalNote{Text: "patient is 80 kilos"},
+			expectedWeight: qty(80, "kg"),
+		},
+		{
+			desc:         "keyword missing from the site lexicon is not read",
+			clinicalNote: &model.ClinicalNote{Text: "ht 180 cm"},
+		},
+		{
+			desc:         "unit spelling missing from the site lexicon is not read",
+			clinicalNote: &model.ClinicalNote{Text: "weight 80 kilograms"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			lexicon, err := service.ParseLexicon([]byte(siteLexicon))
+			require.NoError(t, err)
+			testService, err := service.NewParserService(testsupport.Logger(), service.Config{}, service.LexiconExtractorRegistry(service.Lexicons{lexicon.Language: lexicon}))
+			require.NoError(t, err)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			assert.Equal(t, tt.expectedBirthWeight, healthMetric.BirthWeight)
+			assert.Equal(t, tt.expectedHeight, healthMetric.Height)
+		})
+	}
+}
+
+func TestParseLexicon_RejectsMalformedEntries(t *testing.T) {
+	tests := []struct {
+		desc          string
+		lexicon       string
+		expectedError string
+	}{
+		{
+			desc:          "not JSON",
+			lexicon:       `weight: kg`,
+			expectedError: "unable to parse lexicon: invalid character 'w' looking for beginning of value",
+		},
+		{
+			desc:          "unknown field",
+			lexicon:       `{"weight": {"keywords": ["weight"], "units": {"kg": ["kg"]}, "spellings": []}}`,
+			expectedError: `unable to parse lexicon: json: unknown field "spellings"`,
+		},
+		{
+			desc:          "entry without keywords",
+			lexicon:       `{"weight": {"units": {"kg": ["kg"]}}}`,
+			expectedError: "invalid lexicon: weight has no keywords",
+		},
+		{
+			desc:          "empty keyword",
+			lexicon:       `{"weight": {"keywords": ["weight", " "], "units": {"kg": ["kg"]}}}`,
+			expectedError: "invalid lexicon: weight keyword 2 is empty",
+		},
+		{
+			desc:          "birth weight units",
+			lexicon:       `{"birth_weight": {"keywords": ["bw"], "units": {"g": ["g"]}}}`,
+			expectedError: "invalid lexicon: birth_weight units are taken from weight and cannot be given",
+		},
+		{
+			desc: "unit of the wrong dimension",
+			lexicon: `{"weight": {"keywords": ["weight"], "units": {"cm": ["cm"]}},
+				"birth_weight": {"keywords": ["bw"]}, "height": {"keywords": ["height"], "units": {"cm": ["cm"]}}}`,
+			expectedError: "invalid lexicon: weight unit cm is a length, not a mass",
+		},
+		{
+			desc: "spelling given for two units",
+			lexicon: `{"weight": {"keywords": ["weight"], "units": {"kg": ["kg", "k"], "g": ["g", "K"]}},
+				"birth_weight": {"keywords": ["bw"]}, "height": {"keywords": ["height"], "units": {"cm": ["cm"]}}}`,
+			expectedError: `invalid lexicon: weight spelling "k" is given for both g and kg`,
+		},
+		{
+			desc: "unit without spellings",
+			lexicon: `{"weight": {"keywords": ["weight"], "units": {"kg": []}},
+				"birth_weight": {"keywords": ["bw"]}, "height": {"keywords": ["height"], "units": {"cm": ["cm"]}}}`,
+			expectedError: "invalid lexicon: weight unit kg has no spellings",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := service.ParseLexicon([]byte(tt.lexicon))
+
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+// drugNames are the medicines the dose extractor recognises; a dose is only read after one of them, so that
+// "weight 3400 g" is never taken for a dose.
+var drugNames = []string{
+	"paracetamol", "acetaminophen", "ibuprofen", "aspirin", "diclofenac", "naproxen",
+	"morphine", "oxycodone", "codeine", "tramadol", "fentanyl",
+	"enoxaparin", "dalteparin", "tinzaparin", "heparin", "warfarin", "apixaban", "rivaroxaban",
+	"gentamicin", "amikacin", "vancomycin", "teicoplanin", "amoxicillin", "co-amoxiclav", "flucloxacillin",
+	"benzylpenicillin", "ceftriaxone", "cefotaxime", "cefuroxime", "meropenem", "piperacillin", "tazocin",
+	"clarithromycin", "azithromycin", "erythromycin", "metronidazole", "ciprofloxacin", "doxycycline", "aciclovir",
+	"ondansetron", "metoclopramide", "cyclizine", "omeprazole", "lansoprazole",
+	"prednisolone", "dexamethasone", "hydrocortisone", "methylprednisolone",
+	"furosemide", "bisoprolol", "amlodipine", "ramipril", "digoxin", "atorvastatin", "simvastatin",
+	"metformin", "gliclazide", "levothyroxine", "salbutamol", "phenytoin", "levetiracetam", "caffeine",
+}
+
+const (
+	routePattern     = `po|oral(?:ly)?|iv|intravenous(?:ly)?|im|intramuscular(?:ly)?|s/c|sc|sq|subcut|subcutaneous(?:ly)?|pr|rectal(?:ly)?|sl|sublingual(?:ly)?|inh|inhaled|nebs?|nebuli[sz]ed|top|topical(?:ly)?`
+	doseUnitPattern  = `mg|g|mcg|ug|µg|μg|micrograms?|milligrams?`
+	doseValuePattern = `(\d{1,5}(?:\.\d{1,3})?)\s*(` + doseUnitPattern + `)\b`
+	doseBasisPattern = `day|d|24\s*(?:h|hrs?|hours?)|dose`
+	// doseTailLimit bounds how far after the dose the route and frequency are looked for.
+	doseTailLimit = 40
+)
+
+var (
+	// doseRegex captures the drug, any route written before the dose, the dose and its unit, whether it is per kg
+	// and whether that is per day or per dose, then any absolute dose written beside it: "gentamicin 7mg/kg
+	// (490 mg)", "enoxaparin sc 1 mg/kg = 80mg" or "vancomycin 60 mg/kg/day".
+	doseRegex = regexp.MustCompile(`(?i)\b(` + longestFirst(quotedDrugNames()) + `)\b\s*(?:(` + routePattern + `)\b\s*)?` +
+		doseValuePattern + `(\s*(?:/|per)\s*kg\b(?:\s*(?:/|per)\s*(` + doseBasisPattern + `)\b)?)?` +
+		`(?:\s*(?:\(\s*|=\s*|i\.e\.?\s*)` + doseValuePattern + `\s*\)?)?`)
+	routeRegex = regexp.MustCompile(`(?i)\b(` + routePattern + `)\b`)
+	// frequencyRegex captures the interval of "q6h", "every 6 hours" and "6 hourly".
+	frequencyRegex = regexp.MustCompile(`(?i)\b(?:od|once\s+(?:daily|a\s+day)|daily|bd|bid|twice\s+(?:daily|a\s+day)|tds|tid|three\s+times\s+(?:daily|a\s+day)|qds|qid|four\s+times\s+(?:daily|a\s+day)|mane|nocte|at\s+night|prn|as\s+(?:required|needed)|stat|q\s*(\d{1,2})\s*h|every\s+(\d{1,2})\s+hours?|(\d{1,2})\s*(?:-\s*)?hourly)\b`)
+	// doseTailEnd stops the search for a route and frequency at the end of the clause.
+	doseTailEnd = regexp.MustCompile(`[,;\n]|\.\s`)
+)
+
+var routes = map[string]string{
+	"po": model.RouteOral, "oral": model.RouteOral, "orally": model.RouteOral,
+	"iv": model.RouteIntravenous, "intravenous": model.RouteIntravenous, "intravenously": model.RouteIntravenous,
+	"im": model.RouteIntramuscular, "intramuscular": model.RouteIntramuscular, "intramuscularly": model.RouteIntramuscular,
+	"s/c": model.RouteSubcutaneous, "sc": model.RouteSubcutaneous, "sq": model.RouteSubcutaneous,
+	"subcut": model.RouteSubcutaneous, "subcutaneous": model.RouteSubcutaneous, "subcutaneously": model.RouteSubcutaneous,
+	"pr": model.RouteRectal, "rectal": model.RouteRectal, "rectally": model.RouteRectal,
+	"sl": model.RouteSublingual, "sublingual": model.RouteSublingual, "sublingually": model.RouteSublingual,
+	"inh": model.RouteInhaled, "inhaled": model.RouteInhaled, "neb": model.RouteInhaled, "nebs": model.RouteInhaled,
+	"nebulised": model.RouteInhaled, "nebulized": model.RouteInhaled,
+	"top": model.RouteTopical, "topical": model.RouteTopical, "topically": model.RouteTopical,
+}
+
+var frequencies = map[string]string{
+	"od": model.FrequencyOnceDaily, "once daily": model.FrequencyOnceDaily, "once a day": model.FrequencyOnceDaily,
+	"daily": model.FrequencyOnceDaily,
+	"bd":    model.FrequencyTwiceDaily, "bid": model.FrequencyTwiceDaily, "twice daily": model.FrequencyTwiceDaily,
+	"twice a day": model.FrequencyTwiceDaily,
+	"tds":         model.FrequencyThreeTimesDaily, "tid": model.FrequencyThreeTimesDaily,
+	"three times daily": model.FrequencyThreeTimesDaily, "three times a day": model.FrequencyThreeTimesDaily,
+	"qds": model.FrequencyFourTimesDaily, "qid": model.FrequencyFourTimesDaily,
+	"four times daily": model.FrequencyFourTimesDaily, "four times a day": model.FrequencyFourTimesDaily,
+	"mane": model.FrequencyInTheMorning, "nocte": model.FrequencyAtNight, "at night": model.FrequencyAtNight,
+	"prn": model.FrequencyAsNeeded, "as required": model.FrequencyAsNeeded, "as needed": model.FrequencyAsNeeded,
+	"stat": model.FrequencyOnce,
+}
+
+func quotedDrugNames() []string {
+	quoted := make([]string, len(drugNames))
+	for i, name := range drugNames {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+
+	return quoted
+}
+
+// extractMedicationDoseMetrics returns every dose in the note as written, in order of appearance. An absolute
+// dose written beside a per-kg dose is returned as a stated dose observation sharing its span; beside an absolute
+// dose it only repeats it and is ignored.
+func extractMedicationDoseMetrics(text string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, m := range doseRegex.FindAllStringSubmatchIndex(text, -1) {
+		dose, err := parseDose(text, m, 3)
+		if err != nil {
+			return nil, err
+		}
+		if m[10] >= 0 {
+			dose.Unit += "/kg"
+		}
+		if m[12] >= 0 && !strings.EqualFold(text[m[12]:m[13]], "dose") {
+			dose.Unit += "/day"
+		}
+		drug := strings.ToLower(text[m[2]:m[3]])
+		route := ""
+		if m[4] >= 0 {
+			route = routes[strings.ToLower(text[m[4]:m[5]])]
+		}
+		route, frequency, end := doseTail(text, m[1], route)
+
+		observation := newObservation(text, m[0], end, model.KindMedicationDose, dose)
+		observation.Drug, observation.Route, observation.Frequency = drug, route, frequency
+		observations = append(observations, observation)
+		if m[10] < 0 || m[14] < 0 {
+			continue
+		}
+		stated, err := parseDose(text, m, 7)
+		if err != nil {
+			return nil, err
+		}
+		statedObservation := newObservation(text, m[0], end, model.KindStatedDose, stated)
+		statedObservation.Drug, statedObservation.Route, statedObservation.Frequency = drug, route, frequency
+		observations = append(observations, statedObservation)
+	}
+
+	return observations, nil
+}
+
+// parseDose reads the dose whose value is in the given group and unit in the next.
+func parseDose(text string, m []int, group int) (quantity.Quantity, error) {
+	valStr := text[m[2*group]:m[2*group+1]]
+	v, err := strconv.ParseFloat(valStr, 64)
+	if err != nil {
+		return quantity.Quantity{}, fmt.Errorf("unable to parse given dose of %s ", valStr)
+	}
+	unit, err := quantity.Parse(text[m[2*group+2]:m[2*group+3]])
+	if err != nil {
+		return quantity.Quantity{}, fmt.Errorf("unable to parse given dose of %s: %w", text[m[0]:m[1]], err)
+	}
+
+	return quantity.Quantity{Value: v, Unit: unit.Code}, nil
+}
+
+// doseTail looks for the route, unless it was written before the dose, and the frequency in the rest of the
+// clause after the dose. It returns the end of the mention, which takes in whichever of them was found.
+func doseTail(text string, start int, route string) (string, string, int) {
+	end := min(len(text), start+doseTailLimit)
+	if m := doseTailEnd.FindStringIndex(text[start:end]); m != nil {
+		end = start + m[0]
+	}
+	tail, mentionEnd := text[start:end], start
+	if route == "" {
+		if m := routeRegex.FindStringSubmatchIndex(tail); m != nil {
+			route = routes[strings.ToLower(tail[m[2]:m[3]])]
+			mentionEnd = max(mentionEnd, start+m[1])
+		}
+	}
+	frequency := ""
+	if m := frequencyRegex.FindStringSubmatchIndex(tail); m != nil {
+		frequency = doseFrequency(tail, m)
+		mentionEnd = max(mentionEnd, start+m[1])
+	}
+
+	return route, frequency, mentionEnd
+}
+
+func doseFrequency(tail string, m []int) string {
+	for group := 1; group <= 3; group++ {
+		if m[2*group] >= 0 {
+			return "every_" + tail[m[2*group]:m[2*group+1]] + "_hours"
+		}
+	}
+
+	return frequencies[strings.ToLower(strings.Join(strings.Fields(tail[m[0]:m[1]]), " "))]
+}
+
+// selectMedications reports every dose that is not negated, historical or held back for review. A per-kg dose is
+// multiplied out for the primary weight and checked against any absolute dose written beside it. A dose per kg
+// per day is only given as a daily total, as the note may not say how many doses it is divided into, and a dose
+// written beside it is checked against that total.
+func selectMedications(observations []model.Observation, weight *model.Observation, tolerance float64) ([]model.Medication, error) {
+	var weightKg *quantity.Quantity
+	if weight != nil {
+		kg, err := weight.Quantity().To("kg")
+		if err != nil {
+			return nil, err
+		}
+		weightKg = &kg
+	}
+
+	var medications []model.Medication
+	for _, o := range observations {
+		if o.Kind != model.KindMedicationDose || !isCurrent(o) {
+			continue
+		}
+		medication := model.Medication{Drug: o.Drug, Dose: o.Value, Unit: o.Unit, Route: o.Route, Frequency: o.Frequency}
+		medication.StatedDose = statedDose(observations, o)
+		absolute, perDay := strings.CutSuffix(o.Unit, "/kg/day")
+		perKg := perDay
+		if !perDay {
+			absolute, perKg = strings.CutSuffix(o.Unit, "/kg")
+		}
+		if perKg && weightKg != nil {
+			calculated := quantity.Quantity{Value: o.Value * weightKg.Value, Unit: absolute}.Round(2)
+			if perDay {
+				medication.DailyDose = &calculated
+			} else {
+				medication.CalculatedDose = &calculated
+			}
+			if medication.StatedDose != nil {
+				stated, err := medication.StatedDose.To(absolute)
+				if err != nil {
+					return nil, err
+				}
+				medication.DoseMismatch = math.Abs(stated.Round(2).Value-calculated.Value) > tolerance*calculated.Value
+			}
+		}
+		medications = append(medications, medication)
+	}
+
+	return medications, nil
+}
+
+func statedDose(observations []model.Observation, dose model.Observation) *quantity.Quantity {
+	for _, o := range observations {
+		if o.Kind == model.KindStatedDose && o.Start == dose.Start {
+			stated := o.Quantity()
+			return &stated
+		}
+	}
+
+	return nil
+}
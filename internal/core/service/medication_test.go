@@ -0,0 +1,167 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+	"cleo.com/internal/core/service"
+	"cleo.com/testsupport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForMedicationDoses(t *testing.T) {
+	tests := []struct {
+		desc                string
+		clinicalNote        *model.ClinicalNote
+		expectedMedications []model.Medication
+	}{
+		{
+			desc:         "per-kg dose with frequency and no weight",
+			clinicalNote: &model.ClinicalNote{Text: "enoxaparin 1 mg/kg bd"},
+			expectedMedications: []model.Medication{
+				{Drug: "enoxaparin", Dose: 1, Unit: "mg/kg", Frequency: model.FrequencyTwiceDaily},
+			},
+		},
+		{
+			desc:         "per-kg dose multiplied out for the weight",
+			clinicalNote: &model.ClinicalNote{Text: "weight 70 kg. Gentamicin 7mg/kg IV"},
+			expectedMedications: []model.Medication{
+				{Drug: "gentamicin", Dose: 7, Unit: "mg/kg", Route: model.RouteIntravenous, CalculatedDose: qty(490, "mg")},
+			},
+		},
+		{
+			desc:         "per-kg dose is not multiplied out for a goal weight",
+			clinicalNote: &model.ClinicalNote{Text: "goal wt 65kg, gentamicin 7mg/kg"},
+			expectedMedications: []model.Medication{
+				{Drug: "gentamicin", Dose: 7, Unit: "mg/kg"},
+			},
+		},
+		{
+			desc:         "dose in the plan is still reported",
+			clinicalNote: &model.ClinicalNote{Text: "weight 70 kg\nPlan: gentamicin 7mg/kg"},
+			expectedMedications: []model.Medication{
+				{Drug: "gentamicin", Dose: 7, Unit: "mg/kg", CalculatedDose: qty(490, "mg")},
+			},
+		},
+		{
+			desc:         "absolute dose with route and frequency",
+			clinicalNote: &model.ClinicalNote{Text: "paracetamol 1g po qds"},
+			expectedMedications: []model.Medication{
+				{Drug: "paracetamol", Dose: 1, Unit: "g", Route: model.RouteOral, Frequency: model.FrequencyFourTimesDaily},
+			},
+		},
+		{
+			desc:         "route before the dose and an interval frequency",
+			clinicalNote: &model.ClinicalNote{Text: "morphine sc 2.5 mg q4h prn"},
+			expectedMedications: []model.Medication{
+				{Drug: "morphine", Dose: 2.5, Unit: "mg", Route: model.RouteSubcutaneous, Frequency: "every_4_hours"},
+			},
+		},
+		{
+			desc:         "stated dose in agreement",
+			clinicalNote: &model.ClinicalNote{Text: "weight 80 kg, enoxaparin 1 mg/kg (80 mg) sc bd"},
+			expectedMedications: []model.Medication{
+				{Drug: "enoxaparin", Dose: 1, Unit: "mg/kg", Route: model.RouteSubcutaneous, Frequency: model.FrequencyTwiceDaily,
+					CalculatedDose: qty(80, "mg"), StatedDose: qty(80, "mg")},
+			},
+		},
+		{
+			desc:         "stated dose that disagrees",
+			clinicalNote: &model.ClinicalNote{Text: "weight 70 kg, gentamicin 7mg/kg = 560 mg"},
+			expectedMedications: []model.Medication{
+				{Drug: "gentamicin", Dose: 7, Unit: "mg/kg", CalculatedDose: qty(490, "mg"), StatedDose: qty(560, "mg"), DoseMismatch: true},
+			},
+		},
+		{
+			desc:         "stated dose in another unit",
+			clinicalNote: &model.ClinicalNote{Text: "weight 10 kg, vancomycin 15 mg/kg (0.15 g)"},
+			expectedMedications: []model.Medication{
+				{Drug: "vancomycin", Dose: 15, Unit: "mg/kg", CalculatedDose: qty(150, "mg"), StatedDose: qty(0.15, "g")},
+			},
+		},
+		{
+			desc:         "per-kg dose per day given as a daily total",
+			clinicalNote: &model.ClinicalNote{Text: "vancomycin 60 mg/kg/day in divided doses, weight 70kg"},
+			expectedMedications: []model.Medication{
+				{Drug: "vancomycin", Dose: 60, Unit: "mg/kg/day", DailyDose: qty(4200, "mg")},
+			},
+		},
+		{
+			desc:         "stated daily dose that disagrees",
+			clinicalNote: &model.ClinicalNote{Text: "weight 70kg, vancomycin 60 mg/kg/day (2000 mg)"},
+			expectedMedications: []model.Medication{
+				{Drug: "vancomycin", Dose: 60, Unit: "mg/kg/day", DailyDose: qty(4200, "mg"), StatedDose: qty(2000, "mg"), DoseMismatch: true},
+			},
+		},
+		{
+			desc:         "stated daily dose in agreement",
+			clinicalNote: &model.ClinicalNote{Text: "weight 70kg, vancomycin 60 mg/kg/day (4.2 g)"},
+			expectedMedications: []model.Medication{
+				{Drug: "vancomycin", Dose: 60, Unit: "mg/kg/day", DailyDose: qty(4200, "mg"), StatedDose: qty(4.2, "g")},
+			},
+		},
+		{
+			desc:         "per-kg dose per 24 hours",
+			clinicalNote: &model.ClinicalNote{Text: "weight 10 kg, paracetamol 60 mg/kg per 24h"},
+			expectedMedications: []model.Medication{
+				{Drug: "paracetamol", Dose: 60, Unit: "mg/kg/day", DailyDose: qty(600, "mg")},
+			},
+		},
+		{
+			desc:         "per-kg dose stated per dose",
+			clinicalNote: &model.ClinicalNote{Text: "weight 70 kg, gentamicin 7mg/kg/dose (490 mg)"},
+			expectedMedications: []model.Medication{
+				{Drug: "gentamicin", Dose: 7, Unit: "mg/kg", CalculatedDose: qty(490, "mg"), StatedDose: qty(490, "mg")},
+			},
+		},
+		{
+			desc:         "dose taken without food is not negated",
+			clinicalNote: &model.ClinicalNote{Text: "paracetamol 1g po without food"},
+			expectedMedications: []model.Medication{
+				{Drug: "paracetamol", Dose: 1, Unit: "g", Route: model.RouteOral},
+			},
+		},
+		{
+			desc:         "negated dose is left out",
+			clinicalNote: &model.ClinicalNote{Text: "not given enoxaparin 40 mg, paracetamol 500 mg"},
+			expectedMedications: []model.Medication{
+				{Drug: "paracetamol", Dose: 500, Unit: "mg"},
+			},
+		},
+		{
+			desc:         "weight alone is not a dose",
+			clinicalNote: &model.ClinicalNote{Text: "weight 3400 g"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMedications, healthMetric.Medications)
+		})
+	}
+}
+
+func TestParserService_ParseClinicalNote_ForDoseTolerance(t *testing.T) {
+	testService, err := service.NewParserService(testsupport.Logger(), service.Config{DoseTolerance: 0.2}, service.DefaultExtractorRegistry())
+	require.NoError(t, err)
+	healthMetric, err := testService.ParseClinicalNote(&model.ClinicalNote{Text: "weight 70 kg, gentamicin 7mg/kg = 560 mg"})
+
+	require.NoError(t, err)
+	require.Len(t, healthMetric.Medications, 1)
+	assert.Equal(t, &quantity.Quantity{Value: 490, Unit: "mg"}, healthMetric.Medications[0].CalculatedDose)
+	assert.False(t, healthMetric.Medications[0].DoseMismatch)
+}
+
+func TestNewParserService_RejectsDoseToleranceOutOfRange(t *testing.T) {
+	_, err := service.NewParserService(testsupport.Logger(), service.Config{DoseTolerance: -0.1}, service.DefaultExtractorRegistry())
+
+	assert.EqualError(t, err, "dose tolerance of -0.1 is outside 0 to 1 in parser config")
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"cleo.com/internal/core/domain/model"
+)
+
+// NegEx-style trigger phrases. A trigger only applies to a mention within the same phrase, so that
+// "weight not taken, previous wt 80kg" marks the 80kg as historical rather than negated. A bare "no" only
+// negates a mention after it, and pseudo-triggers such as "no change" or "without food" negate nothing.
+var (
+	negationTriggerRegex   = regexp.MustCompile(`(?i)\b(?:never|without|denies|cannot|could not|unable to|declined|refused|withheld|not (?:recorded|taken|measured|done|weighed|obtained|documented|given))\b`)
+	precedingNegationRegex = regexp.MustCompile(`(?i)\bno\b`)
+	pseudoTriggerRegex     = regexp.MustCompile(`(?i)\b(?:no (?:significant |further )?change|no further|not only|without (?:food|meals?|milk|water|supplemental (?:o2|oxygen)|o2|oxygen|support|shoes|clothes|clothing))\b`)
+	historicalTriggerRegex = regexp.MustCompile(`(?i)\b(?:previous(?:ly)?|prior|formerly|usual|pre-?morbid|history of|last (?:visit|year|month|week|admission)|\d+\s+(?:days?|weeks?|months?|years?)\s+ago)\b`)
+	uncertainTriggerRegex  = regexp.MustCompile(`(?i)\?|\b(?:possibly|probably|maybe|may be|might be|query|unsure|uncertain|unclear|unverified|not verified)\b`)
+)
+
+type statusPattern struct {
+	status  string
+	pattern *regexp.Regexp
+}
+
+// measurementStatusPatterns recognise an explicit statement that a measurement was not taken.
+var measurementStatusPatterns = map[string][]statusPattern{
+	model.KindWeight: {
+		{status: model.StatusDeclined, pattern: regexp.MustCompile(`(?i)\b(?:(?:weight|wt|weighing)\s+(?:was\s+)?(?:declined|refused)|(?:declined|refused)\s+(?:to\s+be\s+)?(?:weigh(?:ed|ing)?|weight))\b`)},
+		{status: model.StatusUnobtainable, pattern: regexp.MustCompile(`(?i)\b(?:(?:unable|not able|could not|cannot)\s+(?:to\s+)?(?:be\s+)?weigh(?:ed)?|(?:weight|wt)\s+(?:was\s+)?(?:unobtainable|not obtainable|unable to be (?:obtained|measured)))\b`)},
+		{status: model.StatusNotRecorded, pattern: regexp.MustCompile(`(?i)\b(?:(?:weight|wt)\s+(?:was\s+)?not\s+(?:recorded|taken|measured|done|documented|available)|no\s+(?:weight|wt)\s+(?:recorded|taken|documented|available))\b`)},
+	},
+	model.KindHeight: {
+		{status: model.StatusDeclined, pattern: regexp.MustCompile(`(?i)\b(?:(?:height|ht)\s+(?:measurement\s+)?(?:was\s+)?(?:declined|refused)|(?:declined|refused)\s+(?:to\s+be\s+)?(?:measured|height))\b`)},
+		{status: model.StatusUnobtainable, pattern: regexp.MustCompile(`(?i)\b(?:(?:unable|not able|could not|cannot)\s+(?:to\s+)?(?:measure\s+height|stand)|(?:height|ht)\s+(?:was\s+)?(?:unobtainable|not obtainable|unable to be (?:obtained|measured)))\b`)},
+		{status: model.StatusNotRecorded, pattern: regexp.MustCompile(`(?i)\b(?:(?:height|ht)\s+(?:was\s+)?not\s+(?:recorded|taken|measured|done|documented|available)|no\s+(?:height|ht)\s+(?:recorded|taken|documented|available))\b`)},
+	},
+}
+
+// annotateNegation marks each observation as negated, historical or uncertain from the trigger phrases
+// in the phrase around it. A relative date such as "2 days ago" that was placed in time is not a historical
+// trigger, so the value competes on its date instead.
+func annotateNegation(text string, observations []model.Observation, dates []dateMention) {
+	spans := observationSpans(observations)
+	for i := range observations {
+		o := &observations[i]
+		from, to := contextBounds(text, o.Start, o.End, spans, trailingBoundaryRegex)
+		scope := text[from:to]
+		unpseudo := pseudoTriggerRegex.ReplaceAllStringFunc(scope, func(s string) string { return strings.Repeat(" ", len(s)) })
+		o.Negated = negationTriggerRegex.MatchString(unpseudo) || precedingNegationRegex.MatchString(unpseudo[:o.Start-from])
+		o.Historical = historicalTriggerRegex.MatchString(withoutDates(text, from, to, dates))
+		o.Uncertain = uncertainTriggerRegex.MatchString(scope)
+	}
+}
+
+// measurementStatus reports whether the note explicitly says a measurement of the given kind was declined,
+// unobtainable or not recorded.
+func measurementStatus(text, kind string) string {
+	for _, p := range measurementStatusPatterns[kind] {
+		if p.pattern.MatchString(text) {
+			return p.status
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForNegatedAndMissingMeasurements(t *testing.T) {
+	type flags struct {
+		negated, historical, uncertain bool
+	}
+
+	tests := []struct {
+		desc                 string
+		clinicalNote         *model.ClinicalNote
+		expectedWeight       *quantity.Quantity
+		expectedWeightStatus string
+		expectedHeightStatus string
+		expectedFlags        []flags
+	}{
+		{
+			desc:                 "previous weight is historical when the current one was not taken",
+			clinicalNote:         &model.ClinicalNote{Text: "weight not taken, previous wt 80kg"},
+			expectedWeight:       nil,
+			expectedWeightStatus: model.StatusNotRecorded,
+			expectedFlags:        []flags{{historical: true}},
+		},
+		{
+			desc:           "no status once a later weight is reported",
+			clinicalNote:   &model.ClinicalNote{Text: "weight not recorded on admission. Today weight 80kg"},
+			expectedWeight: qty(80, "kg"),
+			expectedFlags:  []flags{{}},
+		},
+		{
+			desc:                 "weighing declined",
+			clinicalNote:         &model.ClinicalNote{Text: "Patient declined to be weighed today"},
+			expectedWeightStatus: model.StatusDeclined,
+		},
+		{
+			desc:                 "unable to weigh",
+			clinicalNote:         &model.ClinicalNote{Text: "unable to weigh as bedbound. Height not measured"},
+			expectedWeightStatus: model.StatusUnobtainable,
+			expectedHeightStatus: model.StatusNotRecorded,
+		},
+		{
+			desc:                 "height refused",
+			clinicalNote:         &model.ClinicalNote{Text: "height refused, wt 70kg"},
+			expectedWeight:       qty(70, "kg"),
+			expectedHeightStatus: model.StatusDeclined,
+			expectedFlags:        []flags{{}},
+		},
+		{
+			desc:           "negated mention is not reported",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg not measured"},
+			expectedWeight: nil,
+			expectedFlags:  []flags{{negated: true}},
+		},
+		{
+			desc:           "no change after the value is not a negation",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg no change"},
+			expectedWeight: qty(80, "kg"),
+			expectedFlags:  []flags{{}},
+		},
+		{
+			desc:           "no after the value does not negate it",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg no oedema"},
+			expectedWeight: qty(80, "kg"),
+			expectedFlags:  []flags{{}},
+		},
+		{
+			desc:           "no significant change before the value",
+			clinicalNote:   &model.ClinicalNote{Text: "no significant change in weight 80kg"},
+			expectedWeight: qty(80, "kg"),
+			expectedFlags:  []flags{{}},
+		},
+		{
+			desc:           "weighed without shoes is not a negation",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 80kg without shoes"},
+			expectedWeight: qty(80, "kg"),
+			expectedFlags:  []flags{{}},
+		},
+		{
+			desc:           "no before the value negates it",
+			clinicalNote:   &model.ClinicalNote{Text: "no wt 80kg"},
+			expectedWeight: nil,
+			expectedFlags:  []flags{{negated: true}},
+		},
+		{
+			desc:           "uncertain mention gives way to a certain one",
+			clinicalNote:   &model.ClinicalNote{Text: "wt 90kg?; today weight is 88kg"},
+			expectedWeight: qty(88, "kg"),
+			expectedFlags:  []flags{{uncertain: true}, {}},
+		},
+		{
+			desc:           "uncertain mention is still reported when it is the only one",
+			clinicalNote:   &model.ClinicalNote{Text: "weight is 90kg possibly"},
+			expectedWeight: qty(90, "kg"),
+			expectedFlags:  []flags{{uncertain: true}},
+		},
+		{
+			desc:           "usual weight is historical",
+			clinicalNote:   &model.ClinicalNote{Text: "usual weight 72kg. Weight 65kg on ward scales"},
+			expectedWeight: qty(65, "kg"),
+			expectedFlags:  []flags{{historical: true}, {}},
+		},
+		{
+			desc:           "weight from weeks ago is historical",
+			clinicalNote:   &model.ClinicalNote{Text: "wt 75kg 6 weeks ago"},
+			expectedWeight: nil,
+			expectedFlags:  []flags{{historical: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			assert.Equal(t, tt.expectedWeightStatus, healthMetric.WeightStatus)
+			assert.Equal(t, tt.expectedHeightStatus, healthMetric.HeightStatus)
+
+			var actualFlags []flags
+			for _, o := range healthMetric.Observations {
+				actualFlags = append(actualFlags, flags{negated: o.Negated, historical: o.Historical, uncertain: o.Uncertain})
+			}
+			assert.Equal(t, tt.expectedFlags, actualFlags)
+		})
+	}
+}
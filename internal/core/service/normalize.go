@@ -0,0 +1,252 @@
+package service
+
+import (
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"cleo.com/internal/core/domain/model"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizedText is a rewrite of a note that extractors find easier to match, together with the offsets needed
+// to report every match against the original text.
+type normalizedText struct {
+	text string
+	// starts[i] and ends[i] are the original span that byte i of text was written from.
+	starts []int
+	ends   []int
+}
+
+// normalizer builds a normalizedText by copying the original text and replacing spans of it.
+type normalizer struct {
+	original string
+	copied   int
+	text     strings.Builder
+	starts   []int
+	ends     []int
+}
+
+func newNormalizer(original string) *normalizer {
+	return &normalizer{original: original}
+}
+
+// replace copies the original text up to start unchanged and writes replacement in place of start:end.
+func (b *normalizer) replace(start, end int, replacement string) {
+	b.copyTo(start)
+	b.text.WriteString(replacement)
+	for range len(replacement) {
+		b.starts = append(b.starts, start)
+		b.ends = append(b.ends, end)
+	}
+	b.copied = end
+}
+
+func (b *normalizer) copyTo(offset int) {
+	b.text.WriteString(b.original[b.copied:offset])
+	for i := b.copied; i < offset; i++ {
+		b.starts = append(b.starts, i)
+		b.ends = append(b.ends, i+1)
+	}
+	b.copied = offset
+}
+
+func (b *normalizer) done() normalizedText {
+	b.copyTo(len(b.original))
+	return normalizedText{text: b.text.String(), starts: b.starts, ends: b.ends}
+}
+
+// restore moves the spans of observations found in the normalized text back onto the original note.
+func (n normalizedText) restore(original string, observations []model.Observation) {
+	for i := range observations {
+		o := &observations[i]
+		if o.End <= o.Start || o.End > len(n.starts) {
+			continue
+		}
+		start, end := n.originalSpan(o.Start, o.End)
+		runeStart := utf8.RuneCountInString(original[:start])
+		o.Text = original[start:end]
+		o.Start, o.End = start, end
+		o.RuneStart, o.RuneEnd = runeStart, runeStart+utf8.RuneCountInString(o.Text)
+	}
+}
+
+// originalSpan returns the span of the original note that start:end of the normalized text was written from.
+func (n normalizedText) originalSpan(start, end int) (int, int) {
+	return n.starts[start], n.ends[end-1]
+}
+
+var (
+	// vulgarFractionRegex matches a fraction character and any whole number before it: "5½", "5 ½" or "½".
+	vulgarFractionRegex = regexp.MustCompile(`(?:(\d{1,4})[ \x{00A0}]?)?([½¼¾⅓⅔⅛⅜⅝⅞])`)
+	vulgarFractions     = map[string]float64{
+		"½": 0.5, "¼": 0.25, "¾": 0.75, "⅓": 1.0 / 3, "⅔": 2.0 / 3, "⅛": 0.125, "⅜": 0.375, "⅝": 0.625, "⅞": 0.875,
+	}
+	// superscripts keep their meaning as powers, as in kg/m² and 10⁹/L, so are left for the extractors.
+	superscripts = &unicode.RangeTable{R16: []unicode.Range16{
+		{Lo: 0x00B2, Hi: 0x00B3, Stride: 1}, {Lo: 0x00B9, Hi: 0x00B9, Stride: 1}, {Lo: 0x2070, Hi: 0x209F, Stride: 1},
+	}}
+)
+
+// normalizeUnicode rewrites the characters pasted from EHRs and word processors in the plain forms the extractors
+// match: "5½" as "5.5", fullwidth digits and letters as ASCII, "㎏" and "㎝" as "kg" and "cm", and non-breaking and
+// other wide spaces as a plain space. A non-breaking space between digits is kept for normalizeNumberFormat,
+// which reads it as a thousands separator.
+func normalizeUnicode(text string) normalizedText {
+	b := newNormalizer(text)
+	for _, m := range vulgarFractionRegex.FindAllStringSubmatchIndex(text, -1) {
+		v := vulgarFractions[text[m[4]:m[5]]]
+		if m[2] >= 0 {
+			whole, err := strconv.ParseFloat(text[m[2]:m[3]], 64)
+			if err != nil {
+				continue
+			}
+			v += whole
+		}
+		b.replace(m[0], m[1], formatNumber(math.Round(v*100)/100))
+	}
+	fractions := b.done()
+
+	b = newNormalizer(fractions.text)
+	for i := 0; i < len(fractions.text); {
+		n := norm.NFKC.NextBoundaryInString(fractions.text[i:], true)
+		segment := fractions.text[i : i+n]
+		if compatible := norm.NFKC.String(segment); compatible != segment && !keepsForm(fractions.text, i, i+n) {
+			b.replace(i, i+n, compatible)
+		}
+		i += n
+	}
+
+	return fractions.compose(b.done())
+}
+
+// keepsForm reports whether the segment at start:end is a superscript or a non-breaking space between digits.
+func keepsForm(text string, start, end int) bool {
+	r, _ := utf8.DecodeRuneInString(text[start:end])
+	if unicode.Is(superscripts, r) {
+		return true
+	}
+	if r != '\u00A0' && r != '\u202F' {
+		return false
+	}
+
+	return start > 0 && end < len(text) && isDigit(text[start-1]) && isDigit(text[end])
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Spelled-out numbers, built from the grammar of English cardinals so that "five ten" stays two numbers rather
+// than being read as fifteen.
+const (
+	unitWords    = `one|two|three|four|five|six|seven|eight|nine`
+	teenWords    = `ten|eleven|twelve|thirteen|fourteen|fifteen|sixteen|seventeen|eighteen|nineteen`
+	tensWords    = `twenty|thirty|forty|fifty|sixty|seventy|eighty|ninety`
+	belowHundred = `(?:(?:` + tensWords + `)(?:[\s-]+(?:` + unitWords + `))?|(?:` + teenWords + `)|(?:` + unitWords + `))`
+	hundreds     = `(?:(?:a|` + unitWords + `)\s+hundred(?:\s+(?:and\s+)?` + belowHundred + `)?)`
+	thousands    = `(?:(?:a|` + belowHundred + `)\s+thousand(?:\s+(?:and\s+)?(?:` + hundreds + `|` + belowHundred + `))?)`
+	fractions    = `(?:\s+and\s+(?:a|one)\s+half|\s+and\s+(?:a|one)\s+quarter|\s+and\s+three\s+quarters|\s+point(?:\s+(?:zero|` + unitWords + `))+)`
+)
+
+var (
+	// numberWordsRegex captures the whole number and any fraction after it.
+	numberWordsRegex = regexp.MustCompile(`(?i)\b(` + thousands + `|` + hundreds + `|` + belowHundred + `|zero)(` + fractions + `)?\b`)
+	// digitFractionRegex matches the mixed form "5 and a half".
+	digitFractionRegex = regexp.MustCompile(`(?i)\b(\d{1,4})(` + fractions + `)\b`)
+	wordRegex          = regexp.MustCompile(`(?i)[a-z]+`)
+)
+
+var numberWordValues = map[string]float64{
+	"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+	"ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15, "sixteen": 16,
+	"seventeen": 17, "eighteen": 18, "nineteen": 19,
+	"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50, "sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+}
+
+// normalizeNumberWords writes spelled-out numbers as digits, e.g. "seventy-five kilograms" as "75 kilograms",
+// "six foot two" as "6 foot 2" and "5 and a half feet" as "5.5 feet".
+func normalizeNumberWords(text string) normalizedText {
+	b := newNormalizer(text)
+	for _, m := range numberWordsRegex.FindAllStringSubmatchIndex(text, -1) {
+		v := cardinalValue(text[m[2]:m[3]])
+		if m[4] >= 0 {
+			v += fractionValue(text[m[4]:m[5]])
+		}
+		b.replace(m[0], m[1], formatNumber(v))
+	}
+	words := b.done()
+
+	b = newNormalizer(words.text)
+	for _, m := range digitFractionRegex.FindAllStringSubmatchIndex(words.text, -1) {
+		if m[0] > 0 && words.text[m[0]-1] == '.' {
+			continue
+		}
+		v, err := strconv.ParseFloat(words.text[m[2]:m[3]], 64)
+		if err != nil {
+			continue
+		}
+		b.replace(m[0], m[1], formatNumber(v+fractionValue(words.text[m[4]:m[5]])))
+	}
+
+	return words.compose(b.done())
+}
+
+// cardinalValue evaluates a whole number written in words, e.g. "three thousand four hundred and fifty".
+func cardinalValue(phrase string) float64 {
+	var total, current float64
+	for _, word := range wordRegex.FindAllString(strings.ToLower(phrase), -1) {
+		switch word {
+		case "hundred":
+			current = max(current, 1) * 100
+		case "thousand":
+			total += max(current, 1) * 1000
+			current = 0
+		default:
+			// "a" and "and" have no value of their own
+			current += numberWordValues[word]
+		}
+	}
+
+	return total + current
+}
+
+// fractionValue evaluates the fraction after a whole number: "and a half", "and three quarters" or "point two
+// five".
+func fractionValue(phrase string) float64 {
+	words := wordRegex.FindAllString(strings.ToLower(phrase), -1)
+	switch {
+	case slices.Contains(words, "point"):
+		var digits strings.Builder
+		for _, word := range words[slices.Index(words, "point")+1:] {
+			digits.WriteString(strconv.Itoa(int(numberWordValues[word])))
+		}
+		v, _ := strconv.ParseFloat("0."+digits.String(), 64)
+		return v
+	case slices.Contains(words, "quarters"):
+		return 0.75
+	case slices.Contains(words, "quarter"):
+		return 0.25
+	default:
+		return 0.5
+	}
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// compose chains two normalizations of the same note, returning offsets into the original text.
+func (n normalizedText) compose(next normalizedText) normalizedText {
+	composed := normalizedText{text: next.text, starts: make([]int, len(next.text)), ends: make([]int, len(next.text))}
+	for i := range len(next.text) {
+		composed.starts[i] = n.starts[next.starts[i]]
+		composed.ends[i] = n.ends[next.ends[i]-1]
+	}
+
+	return composed
+}
@@ -0,0 +1,226 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForNumberWords(t *testing.T) {
+	tests := []struct {
+		desc          string
+		clinicalNote  *model.ClinicalNote
+		expectedKind  string
+		expectedValue float64
+		expectedText  string
+		expectedStart int
+		expectedEnd   int
+		expectedRunes [2]int
+		expectedBand  string
+	}{
+		{
+			desc:          "hyphenated compound",
+			clinicalNote:  &model.ClinicalNote{Text: "weight seventy-five kilograms"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 75,
+			expectedText:  "weight seventy-five kilograms",
+			expectedEnd:   29,
+			expectedRunes: [2]int{0, 29},
+			expectedBand:  "unknown",
+		},
+		{
+			desc:          "feet and inches in words",
+			clinicalNote:  &model.ClinicalNote{Text: "height six foot two"},
+			expectedKind:  model.KindHeight,
+			expectedValue: 188,
+			expectedText:  "height six foot two",
+			expectedEnd:   19,
+			expectedRunes: [2]int{0, 19},
+			expectedBand:  "unknown",
+		},
+		{
+			desc:          "mixed digits and words",
+			clinicalNote:  &model.ClinicalNote{Text: "height 5 foot two"},
+			expectedKind:  model.KindHeight,
+			expectedValue: 157.5,
+			expectedText:  "height 5 foot two",
+			expectedEnd:   17,
+			expectedRunes: [2]int{0, 17},
+			expectedBand:  "unknown",
+		},
+		{
+			desc:          "and a half after words",
+			clinicalNote:  &model.ClinicalNote{Text: "weighs twelve and a half stone"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 79.38,
+			expectedText:  "weighs twelve and a half stone",
+			expectedEnd:   30,
+			expectedRunes: [2]int{0, 30},
+			expectedBand:  "unknown",
+		},
+		{
+			desc:          "and a half after digits",
+			clinicalNote:  &model.ClinicalNote{Text: "wt 5 and a half kg"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 5.5,
+			expectedText:  "wt 5 and a half kg",
+			expectedEnd:   18,
+			expectedRunes: [2]int{0, 18},
+			expectedBand:  "unknown",
+		},
+		{
+			desc:          "hundreds",
+			clinicalNote:  &model.ClinicalNote{Text: "height one hundred and eighty cm"},
+			expectedKind:  model.KindHeight,
+			expectedValue: 180,
+			expectedText:  "height one hundred and eighty cm",
+			expectedEnd:   32,
+			expectedRunes: [2]int{0, 32},
+			expectedBand:  "unknown",
+		},
+		{
+			desc:          "thousands of grams with an age in words",
+			clinicalNote:  &model.ClinicalNote{Text: "three day old, birth weight three thousand four hundred and fifty grams"},
+			expectedKind:  model.KindBirthWeight,
+			expectedValue: 3.45,
+			expectedText:  "birth weight three thousand four hundred and fifty grams",
+			expectedStart: 15,
+			expectedEnd:   71,
+			expectedRunes: [2]int{15, 71},
+			expectedBand:  "neonate",
+		},
+		{
+			desc:          "decimal point in words",
+			clinicalNote:  &model.ClinicalNote{Text: "temp thirty seven point five"},
+			expectedKind:  model.KindTemperature,
+			expectedValue: 37.5,
+			expectedText:  "temp thirty seven point five",
+			expectedEnd:   28,
+			expectedRunes: [2]int{0, 28},
+			expectedBand:  "unknown",
+		},
+		{
+			desc:          "offsets after multi-byte text",
+			clinicalNote:  &model.ClinicalNote{Text: "Seen in café – weight eighty kg"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 80,
+			expectedText:  "weight eighty kg",
+			expectedStart: 18,
+			expectedEnd:   34,
+			expectedRunes: [2]int{15, 31},
+			expectedBand:  "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			require.Len(t, healthMetric.Observations, 1)
+			o := healthMetric.Observations[0]
+			assert.Equal(t, tt.expectedKind, o.Kind)
+			assert.Equal(t, tt.expectedValue, o.Value)
+			assert.Equal(t, tt.expectedText, o.Text)
+			assert.Equal(t, tt.expectedText, tt.clinicalNote.Text[o.Start:o.End])
+			assert.Equal(t, [2]int{tt.expectedStart, tt.expectedEnd}, [2]int{o.Start, o.End})
+			assert.Equal(t, tt.expectedRunes, [2]int{o.RuneStart, o.RuneEnd})
+			assert.Equal(t, tt.expectedBand, healthMetric.AgeBand)
+		})
+	}
+}
+
+func TestParserService_ParseClinicalNote_ForNumberFormatsAndUnicode(t *testing.T) {
+	tests := []struct {
+		desc          string
+		clinicalNote  *model.ClinicalNote
+		expectedKind  string
+		expectedValue float64
+		expectedText  string
+	}{
+		{
+			desc:          "decimal comma before a unit",
+			clinicalNote:  &model.ClinicalNote{Text: "weight 75,5 kg"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 75.5,
+			expectedText:  "weight 75,5 kg",
+		},
+		{
+			desc:          "decimal comma before a degree sign",
+			clinicalNote:  &model.ClinicalNote{Text: "temp 37,5°C"},
+			expectedKind:  model.KindTemperature,
+			expectedValue: 37.5,
+			expectedText:  "temp 37,5°C",
+		},
+		{
+			desc:          "comma between thousands",
+			clinicalNote:  &model.ClinicalNote{Text: "weight 1,200 g"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 1.2,
+			expectedText:  "weight 1,200 g",
+		},
+		{
+			desc:          "non-breaking space between thousands",
+			clinicalNote:  &model.ClinicalNote{Text: "weight 1 200 g"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 1.2,
+			expectedText:  "weight 1 200 g",
+		},
+		{
+			desc:          "non-breaking space before the value",
+			clinicalNote:  &model.ClinicalNote{Text: "wt: 75 kg"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 75,
+			expectedText:  "wt: 75 kg",
+		},
+		{
+			desc:          "fullwidth digits",
+			clinicalNote:  &model.ClinicalNote{Text: "weight ７５ kg"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 75,
+			expectedText:  "weight ７５ kg",
+		},
+		{
+			desc:          "fraction character",
+			clinicalNote:  &model.ClinicalNote{Text: "weight 10½ kg"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 10.5,
+			expectedText:  "weight 10½ kg",
+		},
+		{
+			desc:          "kilogram symbol",
+			clinicalNote:  &model.ClinicalNote{Text: "weight 75 ㎏"},
+			expectedKind:  model.KindWeight,
+			expectedValue: 75,
+			expectedText:  "weight 75 ㎏",
+		},
+		{
+			desc:          "centimetre symbol",
+			clinicalNote:  &model.ClinicalNote{Text: "height 180㎝"},
+			expectedKind:  model.KindHeight,
+			expectedValue: 180,
+			expectedText:  "height 180㎝",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			require.Len(t, healthMetric.Observations, 1)
+			o := healthMetric.Observations[0]
+			assert.Equal(t, tt.expectedKind, o.Kind)
+			assert.Equal(t, tt.expectedValue, o.Value)
+			assert.Equal(t, tt.expectedText, o.Text)
+			assert.Equal(t, tt.expectedText, tt.clinicalNote.Text[o.Start:o.End])
+		})
+	}
+}
@@ -3,25 +3,17 @@ package service
 import (
 	"fmt"
 	"math"
-	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+	"cleo.com/internal/core/port"
 	"github.com/sirupsen/logrus"
 )
 
-type Metric struct {
-	Value float64 `json:"value"`
-	Unit  string  `json:"unit"`
-}
-
-var (
-	weightRegex = regexp.MustCompile(`(?i)\b(?:weight|wt|weighs)\s*(?:of|is|at|:)?\s*(\d{1,4}(?:\.\d{1,2})?)\s*(kg|kgs|kilogram|kilograms|lb|lbs|pound|pounds)\b`)
-	heightRegex = regexp.MustCompile(`(?i)\b(?:height|ht)\s*(?:of|is|at|:)?\s*(\d{1,5}(?:\.\d{1,2})?)\s*(cm|mm|m|metre|metres|meter|meters|ft|feet|foot|in|inch|inches)\b`)
-)
-
-// apply sensible medical ranges for weight and height
+// apply sensible medical ranges for weight and height when the patient's age is unknown; see ageBands
 const (
 	minWeightKg = 1.0
 	maxWeightKg = 635.0 // world's heaviest recorded approx
@@ -30,154 +22,418 @@ const (
 )
 
 type ParserService struct {
-	logger *logrus.Logger
+	logger          *logrus.Logger
+	extractors      []port.MetricExtractor
+	reviewThreshold float64
+	doseTolerance   float64
+	dateOrder       string
 }
 
-func NewParserService(logger *logrus.Logger) *ParserService {
-	return &ParserService{
-		logger: logger,
+func NewParserService(logger *logrus.Logger, config Config, registry *ExtractorRegistry) (*ParserService, error) {
+	extractors, err := registry.Enabled(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.ReviewThreshold < 0 || config.ReviewThreshold > 1 {
+		return nil, fmt.Errorf("review threshold of %g is outside 0 to 1 in parser config", config.ReviewThreshold)
 	}
+	if config.DoseTolerance < 0 || config.DoseTolerance > 1 {
+		return nil, fmt.Errorf("dose tolerance of %g is outside 0 to 1 in parser config", config.DoseTolerance)
+	}
+	switch config.DateOrder {
+	case "", DateOrderDayFirst, DateOrderMonthFirst:
+	default:
+		return nil, fmt.Errorf("date order %s is not %s or %s in parser config", config.DateOrder, DateOrderDayFirst, DateOrderMonthFirst)
+	}
+
+	return &ParserService{
+		logger:          logger,
+		extractors:      extractors,
+		reviewThreshold: config.ReviewThreshold,
+		doseTolerance:   config.DoseTolerance,
+		dateOrder:       config.DateOrder,
+	}, nil
 }
 
 func (s ParserService) ParseClinicalNote(note *model.ClinicalNote) (*model.HealthMetric, error) {
 	if note == nil {
 		return nil, nil
 	}
-	response := &model.HealthMetric{}
+	response := &model.HealthMetric{UnitSystem: note.Units}
+	if response.UnitSystem == "" {
+		response.UnitSystem = model.UnitSystemMetric
+	}
 
-	weightMetric, err := extractWeightMetric(note.Text)
-	if err != nil {
-		s.logger.Infof("error encountered extracting weight metric %s", err.Error())
-		return nil, err
+	sections := segmentSections(note.Text)
+	characters := normalizeUnicode(note.Text)
+	response.Language = resolveLanguage(note.Language, characters.text)
+	normalized := characters.compose(normalizeNumberFormat(characters.text, response.Language))
+	if response.Language == model.LanguageEnglish {
+		normalized = normalized.compose(normalizeNumberWords(normalized.text))
+	}
+	for _, extractor := range s.extractors {
+		observations, err := extract(extractor, normalized.text, response.Language)
+		if err != nil {
+			s.logger.Infof("error encountered extracting %s metric %s", extractor.Name(), err.Error())
+			return nil, err
+		}
+		normalized.restore(note.Text, observations)
+		response.Observations = append(response.Observations, observations...)
 	}
 
-	if weightMetric != nil {
-		response.Weight = fmt.Sprintf("%g %s", weightMetric.Value, weightMetric.Unit)
+	dates := findDates(note.Text, newDateContext(s.dateOrder, note.ReferenceTime))
+	response.Observations = readDatedRepeats(note.Text, response.Observations, dates)
+	attributeSubjects(note.Text, response.Observations)
+	band := resolveAgeBand(note.AgeDays, note.Text, normalized, response.Observations)
+	response.AgeBand = band.name
+	readBodyWeights(note.Text, response.Observations, band)
+	if err := checkPlausibility(response.Observations, band); err != nil {
+		s.logger.Infof("error encountered validating metrics %s", err.Error())
+		return nil, err
 	}
 
-	heightMetric, err := extractHeightMetric(note.Text)
+	tagSections(sections, response.Observations)
+	attachDates(note.Text, response.Observations, dates)
+	rankObservations(note.Text, response.Observations, dates)
+	annotateNegation(note.Text, response.Observations, dates)
+	scoreConfidence(response.Observations, band)
+	response.NeedsReview = flagForReview(response.Observations, s.reviewThreshold)
+
+	candidates := response.Observations
+	if note.Ranges == model.RangePolicyReject {
+		candidates = withoutRanges(candidates)
+	}
+	weight, reason := selectPrimary(candidates, model.KindWeight)
+	if weight != nil {
+		response.WeightReason = reason
+	}
+	birthWeight, _ := selectPrimary(response.Observations, model.KindBirthWeight)
+	weightChange, _ := selectPrimary(response.Observations, model.KindWeightChange)
+	height, reason := selectPrimary(candidates, model.KindHeight)
+	if height != nil {
+		response.HeightReason = reason
+	}
+	response.WeightStatus = primaryStatus(note.Text, weight, response.Observations, model.KindWeight)
+	response.HeightStatus = primaryStatus(note.Text, height, response.Observations, model.KindHeight)
+	stated, _ := selectPrimary(response.Observations, model.KindBMI)
+	response.BMI = deriveBMI(weight, height, stated, note.BMICutOffs)
+	response.BloodPressure = selectBloodPressure(response.Observations)
+	heartRate, _ := selectPrimary(response.Observations, model.KindHeartRate)
+	response.HeartRate = quantityOf(heartRate)
+	respiratoryRate, _ := selectPrimary(response.Observations, model.KindRespiratoryRate)
+	response.RespiratoryRate = quantityOf(respiratoryRate)
+	saturation, _ := selectPrimary(response.Observations, model.KindOxygenSaturation)
+	response.OxygenSaturation = quantityOf(saturation)
+	response.OxygenSupport, response.OxygenFlow = selectOxygenSupport(response.Observations, saturation)
+	temperature, _ := selectPrimary(response.Observations, model.KindTemperature)
+	labs, err := selectLabs(response.Observations)
+	if err != nil {
+		s.logger.Infof("error encountered converting lab results %s", err.Error())
+		return nil, err
+	}
+	response.Labs = labs
+	medications, err := selectMedications(response.Observations, weight, s.doseTolerance)
 	if err != nil {
-		s.logger.Infof("error encountered extracting height metric %s", err.Error())
+		s.logger.Infof("error encountered checking medication doses %s", err.Error())
 		return nil, err
 	}
+	response.Medications = medications
+	response.WeightEstimated = weight != nil && weight.Estimated
+	response.HeightEstimated = height != nil && height.Estimated
 
-	if heightMetric != nil {
-		response.Height = fmt.Sprintf("%g %s", heightMetric.Value, heightMetric.Unit)
+	if err := s.applyUnitSystem(response, weight, birthWeight, weightChange, height, temperature); err != nil {
+		s.logger.Infof("error encountered converting to %s units %s", response.UnitSystem, err.Error())
+		return nil, err
 	}
 
 	return response, nil
 }
 
-func extractWeightMetric(text string) (*Metric, error) {
-	note := strings.ToLower(text)
-	// TODO seek requirements if more than one instance of weight metric found.  For now return first occurrence of metric
-	if m := weightRegex.FindStringSubmatch(note); m != nil {
-		valStr := m[1]
-		unitStr := normalizeWeightUnit(m[2])
-		v, err := strconv.ParseFloat(valStr, 64)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse given weight of %s ", valStr)
+// extract runs the extractor in the language of the note when its vocabulary depends on it.
+func extract(extractor port.MetricExtractor, text, language string) ([]model.Observation, error) {
+	if localized, ok := extractor.(port.LocalizedMetricExtractor); ok {
+		return localized.ExtractLanguage(text, language)
+	}
+
+	return extractor.Extract(text)
+}
+
+// primaryStatus explains why no primary value of the given kind was reported: the note says it was not measured,
+// the only values were rejected ranges, or they were held back for review. It is empty when a value was reported.
+func primaryStatus(text string, selected *model.Observation, observations []model.Observation, kind string) string {
+	if selected != nil {
+		return ""
+	}
+	status := measurementStatus(text, kind)
+	status = rangeStatus(status, selected, observations, kind)
+
+	return reviewStatus(status, selected, observations, kind)
+}
+
+// applyUnitSystem reports the primary weight, birth weight, weight change, height and temperature in the unit
+// system of the response.
+func (s ParserService) applyUnitSystem(response *model.HealthMetric, weight, birthWeight, weightChange, height, temperature *model.Observation) error {
+	var err error
+	if response.Weight, err = inUnitSystem(weight, response.UnitSystem); err != nil {
+		return err
+	}
+	if response.WeightRange, err = rangeInUnitSystem(weight, response.UnitSystem); err != nil {
+		return err
+	}
+	if response.BirthWeight, err = inUnitSystem(birthWeight, response.UnitSystem); err != nil {
+		return err
+	}
+	if response.WeightChange, err = weightChangeIn(weightChange, response.UnitSystem); err != nil {
+		return err
+	}
+	if response.Height, err = inUnitSystem(height, response.UnitSystem); err != nil {
+		return err
+	}
+	if response.HeightRange, err = rangeInUnitSystem(height, response.UnitSystem); err != nil {
+		return err
+	}
+	response.HeightFeetInches = feetAndInches(response.Height)
+	if response.Temperature, err = inUnitSystem(temperature, response.UnitSystem); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// extractWeightMetrics returns every current weight mention in the note in kg, in order of appearance.
+func (l *Lexicon) extractWeightMetrics(text string) ([]model.Observation, error) {
+	return l.extractWeights(text, model.KindWeight)
+}
+
+// extractBirthWeightMetrics returns every birth weight mention in the note in kg, in order of appearance.
+func (l *Lexicon) extractBirthWeightMetrics(text string) ([]model.Observation, error) {
+	return l.extractWeights(text, model.KindBirthWeight)
+}
+
+// extractWeights returns the weight mentions of the given kind, telling birth weights from current weights by
+// the keyword that introduced them.
+func (l *Lexicon) extractWeights(text, kind string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, m := range l.weightRegex.FindAllStringSubmatchIndex(text, -1) {
+		if (m[2] >= 0) != (kind == model.KindBirthWeight) {
+			continue
 		}
-		if unitStr != "kg" {
-			v = weightToKg(v, unitStr)
+		var (
+			q      quantity.Quantity
+			bounds *model.Range
+			err    error
+		)
+		switch {
+		case m[6] >= 0:
+			q, err = parseStonesAndPounds(text, m)
+		case m[10] >= 0:
+			q, err = parsePoundsAndOunces(text, m)
+		default:
+			q, bounds, err = l.parseRange(text, m, 7)
 		}
-		if isValidWeight(v) {
-			return &Metric{Value: round(v, 2), Unit: "kg"}, nil
-		} else {
-			return nil, fmt.Errorf("invalid weight of %g kg", round(v, 2))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given %s of %s: %w", strings.ReplaceAll(kind, "_", " "), text[m[0]:m[1]], err)
 		}
+		kg, err := q.To("kg")
+		if err != nil {
+			return nil, err
+		}
+		observation := newObservation(text, m[0], m[1], kind, kg.Round(outputPrecision[kind]))
+		observation.Written = &q
+		observation.Range = bounds
+		observation.Estimated = m[4] >= 0
+		observations = append(observations, observation)
 	}
 
-	return nil, nil
+	return observations, nil
 }
-func extractHeightMetric(text string) (*Metric, error) {
-	note := strings.ToLower(text)
-	// TODO seek requirements if more than one instance of height metric found. For now return first occurrence of metric
-	if m := heightRegex.FindStringSubmatch(note); m != nil {
-		valStr := m[1]
-		unitStr := normalizeHeightUnit(m[2])
-		v, err := strconv.ParseFloat(valStr, 64)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse given height of %s ", valStr)
+
+// extractHeightMetrics returns every height mention in the note in cm, in order of appearance.
+func (l *Lexicon) extractHeightMetrics(text string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, m := range l.heightRegex.FindAllStringSubmatchIndex(text, -1) {
+		var (
+			q      quantity.Quantity
+			bounds *model.Range
+			err    error
+		)
+		if m[4] >= 0 {
+			q, err = parseFeetAndInches(text, m)
+		} else {
+			q, bounds, err = l.parseRange(text, m, 4)
+			if err == nil && bounds == nil && q.Unit == "[ft_i]" {
+				q, err = feetDotInches(text[m[8]:m[9]], q)
+			}
 		}
-		if unitStr != "cm" {
-			v = heightToCm(v, unitStr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given height of %s: %w", text[m[0]:m[1]], err)
 		}
-		if isValidHeight(v) {
-			return &Metric{Value: round(v, 1), Unit: "cm"}, nil
-		} else {
-			return nil, fmt.Errorf("invalid height of %g cm", round(v, 2))
+		cm, err := q.To("cm")
+		if err != nil {
+			return nil, err
 		}
+		observation := newObservation(text, m[0], m[1], model.KindHeight, cm.Round(outputPrecision[model.KindHeight]))
+		observation.Written = &q
+		observation.Range = bounds
+		observation.Estimated = m[2] >= 0
+		observations = append(observations, observation)
 	}
 
-	return nil, nil
+	return observations, nil
 }
 
-func normalizeWeightUnit(u string) string {
-	u = strings.ToLower(u)
-	switch u {
-	case "kg", "kgs", "kilogram", "kilograms":
-		return "kg"
-	case "lb", "lbs", "pound", "pounds":
-		return "lb"
-	default:
-		return u
-	}
-}
-
-func normalizeHeightUnit(u string) string {
-	u = strings.ToLower(u)
-	switch u {
-	case "cm":
-		return "cm"
-	case "mm":
-		return "mm"
-	case "m", "meter", "meters", "metre", "metres":
-		return "m"
-	case "ft", "feet", "foot":
-		return "ft"
-	case "in", "inch", "inches":
-		return "in"
-	default:
-		return u
+// parseQuantity reads a value and the unit as it is spelled in the note.
+func parseQuantity(valStr, unitStr string) (quantity.Quantity, error) {
+	v, err := strconv.ParseFloat(valStr, 64)
+	if err != nil {
+		return quantity.Quantity{}, err
 	}
+	unit, err := quantity.Parse(unitStr)
+	if err != nil {
+		return quantity.Quantity{}, err
+	}
+
+	return quantity.New(v, unit.Code)
 }
 
-func weightToKg(val float64, unit string) float64 {
-	switch unit {
-	case "kg":
-		return val
-	case "lb":
-		return val * 0.45359237
-	default:
-		return val
-	}
-}
-
-func heightToCm(val float64, unit string) float64 {
-	switch unit {
-	case "cm":
-		return val
-	case "mm":
-		return val / 10.0
-	case "m":
-		return val * 100.0
-	case "ft":
-		// usually given as 5.9 for 5ft9 or provided as two numbers; here we treat as feet decimal
-		return val * 30.48
-	case "in":
-		return val * 2.54
-	default:
-		return val
+// parseRange reads the value, optional upper bound and unit groups starting at the given group. A range is read
+// as its midpoint, with the bounds returned as written.
+func (l *Lexicon) parseRange(text string, m []int, group int) (quantity.Quantity, *model.Range, error) {
+	unitStr := text[m[2*group+4]:m[2*group+5]]
+	low, err := l.parseQuantity(text[m[2*group]:m[2*group+1]], unitStr)
+	if err != nil || m[2*group+2] < 0 {
+		return low, nil, err
+	}
+	high, err := l.parseQuantity(text[m[2*group+2]:m[2*group+3]], unitStr)
+	if err != nil {
+		return quantity.Quantity{}, nil, err
+	}
+	if high.Value <= low.Value {
+		return quantity.Quantity{}, nil, fmt.Errorf("range of %g to %g does not increase", low.Value, high.Value)
+	}
+
+	return quantity.Quantity{Value: (low.Value + high.Value) / 2, Unit: low.Unit}, &model.Range{Low: low, High: high}, nil
+}
+
+// convertRange converts both bounds of a range, which may be nil.
+func convertRange(r *model.Range, code string, precision int) (*model.Range, error) {
+	if r == nil {
+		return nil, nil
+	}
+	low, err := r.Low.To(code)
+	if err != nil {
+		return nil, err
+	}
+	high, err := r.High.To(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Range{Low: low.Round(precision), High: high.Round(precision)}, nil
+}
+
+// parseStonesAndPounds reads the stones and optional pounds groups of a stones weight match.
+func parseStonesAndPounds(text string, m []int) (quantity.Quantity, error) {
+	stones, err := strconv.ParseFloat(text[m[6]:m[7]], 64)
+	if err != nil {
+		return quantity.Quantity{}, err
+	}
+	q := quantity.Quantity{Value: stones, Unit: "[stone_av]"}
+	if m[8] < 0 {
+		return q, nil
+	}
+	pounds, err := strconv.ParseFloat(text[m[8]:m[9]], 64)
+	if err != nil {
+		return quantity.Quantity{}, err
+	}
+	if pounds >= 14 {
+		return quantity.Quantity{}, fmt.Errorf("pounds remainder of %g is not less than a stone", pounds)
+	}
+
+	return q.Add(quantity.Quantity{Value: pounds, Unit: "[lb_av]"})
+}
+
+// parsePoundsAndOunces reads the pounds and ounces groups of a pounds and ounces weight match.
+func parsePoundsAndOunces(text string, m []int) (quantity.Quantity, error) {
+	pounds, err := strconv.ParseFloat(text[m[10]:m[11]], 64)
+	if err != nil {
+		return quantity.Quantity{}, err
+	}
+	ounces, err := strconv.ParseFloat(text[m[12]:m[13]], 64)
+	if err != nil {
+		return quantity.Quantity{}, err
+	}
+	if ounces >= 16 {
+		return quantity.Quantity{}, fmt.Errorf("ounces remainder of %g is not less than a pound", ounces)
+	}
+
+	return quantity.Quantity{Value: pounds, Unit: "[lb_av]"}.Add(quantity.Quantity{Value: ounces, Unit: "[oz_av]"})
+}
+
+// parseFeetAndInches reads the feet and optional inches groups of a compound imperial height match.
+func parseFeetAndInches(text string, m []int) (quantity.Quantity, error) {
+	feet, err := strconv.ParseFloat(text[m[4]:m[5]], 64)
+	if err != nil {
+		return quantity.Quantity{}, err
 	}
+	q := quantity.Quantity{Value: feet, Unit: "[ft_i]"}
+	if m[6] < 0 {
+		return q, nil
+	}
+	inches, err := strconv.ParseFloat(text[m[6]:m[7]], 64)
+	if err != nil {
+		return quantity.Quantity{}, err
+	}
+	if inches >= 12 {
+		return quantity.Quantity{}, fmt.Errorf("inches remainder of %g is not less than a foot", inches)
+	}
+
+	return q.Add(quantity.Quantity{Value: inches, Unit: "[in_i]"})
 }
 
-func isValidWeight(v float64) bool {
-	return v >= minWeightKg && v <= maxWeightKg && !math.IsNaN(v)
+// feetDotInches reads a lone feet value such as "5.9 ft" as feet and inches when its fraction is a whole number
+// of inches, from .1 to .11, as that is how such heights are written far more often than in decimal feet.
+// Any other value, such as "5.25 ft", stays in decimal feet.
+func feetDotInches(valStr string, q quantity.Quantity) (quantity.Quantity, error) {
+	whole, fraction, ok := strings.Cut(valStr, ".")
+	if !ok || len(fraction) > 2 || strings.HasPrefix(fraction, "0") {
+		return q, nil
+	}
+	inches, err := strconv.Atoi(fraction)
+	if err != nil || inches < 1 || inches > 11 {
+		return q, nil
+	}
+	feet, err := strconv.ParseFloat(whole, 64)
+	if err != nil {
+		return quantity.Quantity{}, err
+	}
+
+	return quantity.Quantity{Value: feet, Unit: "[ft_i]"}.Add(quantity.Quantity{Value: float64(inches), Unit: "[in_i]"})
 }
 
-func isValidHeight(v float64) bool {
-	return v >= minHeightCm && v <= maxHeightCm && !math.IsNaN(v)
+// newObservation records the matched span of text together with its byte and rune offsets.
+func newObservation(text string, start, end int, kind string, q quantity.Quantity) model.Observation {
+	runeStart := utf8.RuneCountInString(text[:start])
+	return model.Observation{
+		Kind:      kind,
+		Text:      text[start:end],
+		Value:     q.Value,
+		Unit:      q.Unit,
+		Start:     start,
+		End:       end,
+		RuneStart: runeStart,
+		RuneEnd:   runeStart + utf8.RuneCountInString(text[start:end]),
+	}
+}
+
+// quantityOf returns the value of a selected observation, or nil when nothing was selected.
+func quantityOf(o *model.Observation) *quantity.Quantity {
+	if o == nil {
+		return nil
+	}
+	q := o.Quantity()
+
+	return &q
 }
 
 func round(x float64, precision int) float64 {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
 	"cleo.com/internal/core/service"
 	"cleo.com/testsupport"
 
@@ -27,74 +28,74 @@ func TestParserService_ParseClinicalNote_ForWeightMetric(t *testing.T) {
 		{
 			desc:           "clinical note has weight metric using keyword sequence weight of 75kg",
 			clinicalNote:   &model.ClinicalNote{Text: "patient has provided a weight of 75 kg"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note has weight metric using keyword sequence: wt of 75kg",
 			clinicalNote:   &model.ClinicalNote{Text: "patient has provided a wt of 75 kg"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note has weight metric using keyword sequence: weighs 75kg",
 			clinicalNote:   &model.ClinicalNote{Text: "patient weighs 75 kg"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note has weight metric using keyword sequence: weight is 75kg",
 			clinicalNote:   &model.ClinicalNote{Text: "patient weight is 75 kg"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note with weight metric repeated, first metric reported",
 			clinicalNote:   &model.ClinicalNote{Text: "patient has provided a weight of 75 kilograms and a weight of 120 pounds"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note has weight metric using keyword sequence: weight is 75kgs",
 			clinicalNote:   &model.ClinicalNote{Text: "patient weight is 75 kgs"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note has weight metric using keyword sequence: weight is 75kilogram",
 			clinicalNote:   &model.ClinicalNote{Text: "patient weight is 75kilogram"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note has weight metric using keyword sequence: weight is 75kilograms",
 			clinicalNote:   &model.ClinicalNote{Text: "patient weight is 75kilograms"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note has weight metric using keyword sequence: weight is 165.34lb",
 			clinicalNote:   &model.ClinicalNote{Text: "patient weight is 165.34lb"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note has weight metric using keyword sequence: weight is 165.34lbs",
 			clinicalNote:   &model.ClinicalNote{Text: "patient weight is 165.34lbs"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note has weight metric using keyword sequence: weight is 165.34 pound",
 			clinicalNote:   &model.ClinicalNote{Text: "patient weight is 165.34 pound"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note has weight metric using keyword sequence: weight is 165.34 pounds",
 			clinicalNote:   &model.ClinicalNote{Text: "patient weight is 165.34 pounds"},
-			expectedMetric: &model.HealthMetric{Weight: "75 kg"},
+			expectedMetric: &model.HealthMetric{Weight: qty(75, "kg")},
 		},
 		{
 			desc:           "clinical note with invalid weight metrics given in kgs, below min weight",
 			clinicalNote:   &model.ClinicalNote{Text: "patient has provided a weight of 0.5kg"},
 			expectedMetric: nil,
-			expectedError:  errors.New("invalid weight of 0.5 kg"),
+			expectedError:  errors.New("invalid weight of 0.5 kg for age band unknown"),
 		},
 		{
 			desc:           "clinical note with invalid weight metrics given in kgs, exceeding max weight",
 			clinicalNote:   &model.ClinicalNote{Text: "patient has provided a weight of 700kg"},
 			expectedMetric: nil,
-			expectedError:  errors.New("invalid weight of 700 kg"),
+			expectedError:  errors.New("invalid weight of 700 kg for age band unknown"),
 		},
 	}
 
@@ -102,7 +103,7 @@ func TestParserService_ParseClinicalNote_ForWeightMetric(t *testing.T) {
 		tt := tt
 		t.Run(tt.desc, func(t *testing.T) {
 
-			testService := service.NewParserService(testsupport.Logger())
+			testService := newTestParserService(t)
 			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
 
 			if tt.expectedError == nil {
@@ -110,7 +111,7 @@ func TestParserService_ParseClinicalNote_ForWeightMetric(t *testing.T) {
 			} else {
 				assert.Equal(t, tt.expectedError, err)
 			}
-			assert.Equal(t, tt.expectedMetric, healthMetric)
+			assert.Equal(t, tt.expectedMetric, primaryMetric(healthMetric))
 
 		})
 	}
@@ -132,44 +133,44 @@ func TestParserService_ParseClinicalNote_ForHeightMetric(t *testing.T) {
 		{
 			desc:           "clinical note with height metric provided and matched on keyword of",
 			clinicalNote:   &model.ClinicalNote{Text: "height of 100cm"},
-			expectedMetric: &model.HealthMetric{Height: "100 cm"},
+			expectedMetric: &model.HealthMetric{Height: qty(100, "cm")},
 			expectedError:  nil,
 		},
 		{
 			desc:           "clinical note with height metric provided and matched on keyword is",
 			clinicalNote:   &model.ClinicalNote{Text: "height is 100cm"},
-			expectedMetric: &model.HealthMetric{Height: "100 cm"},
+			expectedMetric: &model.HealthMetric{Height: qty(100, "cm")},
 			expectedError:  nil,
 		},
 		{
 			desc:           "clinical note with height metric provided and matched on keyword at",
 			clinicalNote:   &model.ClinicalNote{Text: "height at 100cm"},
-			expectedMetric: &model.HealthMetric{Height: "100 cm"},
+			expectedMetric: &model.HealthMetric{Height: qty(100, "cm")},
 			expectedError:  nil,
 		},
 		{
 			desc:           "clinical note with height metric provided and not matched on any keyword of/at/is",
-			clinicalNote:   &model.ClinicalNote{Text: "height approximately 100cm"},
-			expectedMetric: &model.HealthMetric{Height: ""},
+			clinicalNote:   &model.ClinicalNote{Text: "height nearly 100cm"},
+			expectedMetric: &model.HealthMetric{Height: nil},
 			expectedError:  nil,
 		},
 		{
 			desc:           "clinical note with invalid height metrics given in feet",
 			clinicalNote:   &model.ClinicalNote{Text: "patient has provided a height of 75feet"},
 			expectedMetric: nil,
-			expectedError:  errors.New("invalid height of 2286 cm"),
+			expectedError:  errors.New("invalid height of 2286 cm for age band unknown"),
 		},
 		{
 			desc:           "clinical note with invalid height metrics given in feet",
 			clinicalNote:   &model.ClinicalNote{Text: "patient has provided a height of 75feet"},
 			expectedMetric: nil,
-			expectedError:  errors.New("invalid height of 2286 cm"),
+			expectedError:  errors.New("invalid height of 2286 cm for age band unknown"),
 		},
 		{
 			desc:         "clinical note with height metric repeated",
 			clinicalNote: &model.ClinicalNote{Text: "patient has provided a height of 75 inches and a height of 120 pounds"},
 			expectedMetric: &model.HealthMetric{
-				Height: "190.5 cm",
+				Height: qty(190.5, "cm"),
 			},
 			expectedError: nil,
 		},
@@ -179,7 +180,7 @@ func TestParserService_ParseClinicalNote_ForHeightMetric(t *testing.T) {
 		tt := tt
 		t.Run(tt.desc, func(t *testing.T) {
 
-			testService := service.NewParserService(testsupport.Logger())
+			testService := newTestParserService(t)
 			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
 
 			if tt.expectedError == nil {
@@ -187,9 +188,408 @@ func TestParserService_ParseClinicalNote_ForHeightMetric(t *testing.T) {
 			} else {
 				assert.Equal(t, tt.expectedError, err)
 			}
-			assert.Equal(t, tt.expectedMetric, healthMetric)
+			assert.Equal(t, tt.expectedMetric, primaryMetric(healthMetric))
 
 		})
 	}
 
 }
+
+func TestParserService_ParseClinicalNote_ReportsEveryObservation(t *testing.T) {
+	testService := newTestParserService(t)
+	healthMetric, err := testService.ParseClinicalNote(&model.ClinicalNote{
+		Text: "Weight of 75 kilograms, later a wt of 120 pounds. Height is 180cm, ht: 6 feet",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, qty(75, "kg"), healthMetric.Weight)
+	assert.Equal(t, qty(180, "cm"), healthMetric.Height)
+	type span struct {
+		kind, text            string
+		value                 float64
+		unit                  string
+		written               *quantity.Quantity
+		start, end, runeStart int
+	}
+	var actual []span
+	for _, o := range healthMetric.Observations {
+		actual = append(actual, span{o.Kind, o.Text, o.Value, o.Unit, o.Written, o.Start, o.End, o.RuneStart})
+	}
+	assert.Equal(t, []span{
+		{model.KindWeight, "Weight of 75 kilograms", 75, "kg", qty(75, "kg"), 0, 22, 0},
+		{model.KindWeight, "wt of 120 pounds", 54.43, "kg", qty(120, "[lb_av]"), 32, 48, 32},
+		{model.KindHeight, "Height is 180cm", 180, "cm", qty(180, "cm"), 50, 65, 50},
+		{model.KindHeight, "ht: 6 feet", 182.9, "cm", qty(6, "[ft_i]"), 67, 77, 67},
+	}, actual)
+}
+
+func TestParserService_ParseClinicalNote_ReportsRuneOffsets(t *testing.T) {
+	testService := newTestParserService(t)
+	healthMetric, err := testService.ParseClinicalNote(&model.ClinicalNote{Text: "pt café weight 70 kg"})
+	require.NoError(t, err)
+
+	require.Len(t, healthMetric.Observations, 1)
+	observation := healthMetric.Observations[0]
+	assert.Equal(t, "weight 70 kg", observation.Text)
+	assert.Equal(t, 9, observation.Start)
+	assert.Equal(t, 8, observation.RuneStart)
+	assert.Equal(t, 21, observation.End)
+	assert.Equal(t, 20, observation.RuneEnd)
+}
+
+func TestParserService_ParseClinicalNote_RanksCompetingObservations(t *testing.T) {
+	tests := []struct {
+		desc           string
+		clinicalNote   *model.ClinicalNote
+		expectedWeight *quantity.Quantity
+		expectedReason string
+	}{
+		{
+			desc:           "single mention is the only candidate",
+			clinicalNote:   &model.ClinicalNote{Text: "weight of 75 kg"},
+			expectedWeight: qty(75, "kg"),
+			expectedReason: "only candidate",
+		},
+		{
+			desc:           "measured on admission outranks a self-stated weight",
+			clinicalNote:   &model.ClinicalNote{Text: "the patient stated their weight was 75Kg but when admitted their weight was found to be 95kg"},
+			expectedWeight: qty(95, "kg"),
+			expectedReason: "ranked highest of 2 candidates (measured, on admission)",
+		},
+		{
+			desc:           "today outranks on admission",
+			clinicalNote:   &model.ClinicalNote{Text: "on admission weight 95kg. Today weight is 92kg"},
+			expectedWeight: qty(92, "kg"),
+			expectedReason: "ranked highest of 2 candidates (today)",
+		},
+		{
+			desc:           "previous weight is not a candidate",
+			clinicalNote:   &model.ClinicalNote{Text: "previously weight 101kg; weight 88 kg"},
+			expectedWeight: qty(88, "kg"),
+			expectedReason: "only candidate",
+		},
+		{
+			desc:           "target weight is never preferred",
+			clinicalNote:   &model.ClinicalNote{Text: "target weight of 70kg, weight 82kg"},
+			expectedWeight: qty(82, "kg"),
+			expectedReason: "only candidate",
+		},
+		{
+			desc:           "estimated weight ranks below a reported one",
+			clinicalNote:   &model.ClinicalNote{Text: "estimated weight 60kg; pt reports weight of 64 kg"},
+			expectedWeight: qty(64, "kg"),
+			expectedReason: "ranked highest of 2 candidates (reported)",
+		},
+		{
+			desc:           "tied candidates fall back to the earliest mention",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 70kg, weight 72kg"},
+			expectedWeight: qty(70, "kg"),
+			expectedReason: "ranked highest of 2 candidates (no context cues); earliest of tied candidates",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			assert.Equal(t, tt.expectedReason, healthMetric.WeightReason)
+		})
+	}
+}
+
+func TestParserService_ParseClinicalNote_ForCompoundImperialHeight(t *testing.T) {
+	tests := []struct {
+		desc           string
+		clinicalNote   *model.ClinicalNote
+		expectedHeight *quantity.Quantity
+		expectedText   string
+		expectedError  error
+	}{
+		{
+			desc:           "ascii prime and double prime",
+			clinicalNote:   &model.ClinicalNote{Text: `Ht: 5'9" per intake form`},
+			expectedHeight: qty(175.3, "cm"),
+			expectedText:   `Ht: 5'9"`,
+		},
+		{
+			desc:           "two single quotes as double prime",
+			clinicalNote:   &model.ClinicalNote{Text: "height 5' 11'' today"},
+			expectedHeight: qty(180.3, "cm"),
+			expectedText:   "height 5' 11''",
+		},
+		{
+			desc:           "unicode prime and double prime",
+			clinicalNote:   &model.ClinicalNote{Text: "height 6′2″, BMI pending"},
+			expectedHeight: qty(188, "cm"),
+			expectedText:   "height 6′2″",
+		},
+		{
+			desc:           "prime with inches and no double prime",
+			clinicalNote:   &model.ClinicalNote{Text: "ht 5'4 on intake"},
+			expectedHeight: qty(162.6, "cm"),
+			expectedText:   "ht 5'4",
+		},
+		{
+			desc:           "ft and in words",
+			clinicalNote:   &model.ClinicalNote{Text: "height 5 ft 9 in"},
+			expectedHeight: qty(175.3, "cm"),
+			expectedText:   "height 5 ft 9 in",
+		},
+		{
+			desc:           "run together ft and inches",
+			clinicalNote:   &model.ClinicalNote{Text: "ht 5ft9, afebrile"},
+			expectedHeight: qty(175.3, "cm"),
+			expectedText:   "ht 5ft9",
+		},
+		{
+			desc:           "foot and inches words",
+			clinicalNote:   &model.ClinicalNote{Text: "height is 5 foot 9 inches"},
+			expectedHeight: qty(175.3, "cm"),
+			expectedText:   "height is 5 foot 9 inches",
+		},
+		{
+			desc:           "feet with fractional inches",
+			clinicalNote:   &model.ClinicalNote{Text: "height 5 feet 7.5 inches"},
+			expectedHeight: qty(171.5, "cm"),
+			expectedText:   "height 5 feet 7.5 inches",
+		},
+		{
+			desc:           "feet alone",
+			clinicalNote:   &model.ClinicalNote{Text: "height 6 ft"},
+			expectedHeight: qty(182.9, "cm"),
+			expectedText:   "height 6 ft",
+		},
+		{
+			desc:           "feet and inches written as a decimal",
+			clinicalNote:   &model.ClinicalNote{Text: "height 5.9 ft"},
+			expectedHeight: qty(175.3, "cm"),
+			expectedText:   "height 5.9 ft",
+		},
+		{
+			desc:           "feet and two-digit inches written as a decimal",
+			clinicalNote:   &model.ClinicalNote{Text: "height 5.10 ft"},
+			expectedHeight: qty(177.8, "cm"),
+			expectedText:   "height 5.10 ft",
+		},
+		{
+			desc:           "decimal feet that cannot be inches",
+			clinicalNote:   &model.ClinicalNote{Text: "height 5.25 ft"},
+			expectedHeight: qty(160, "cm"),
+			expectedText:   "height 5.25 ft",
+		},
+		{
+			desc:           "prime feet alone",
+			clinicalNote:   &model.ClinicalNote{Text: "height 6' tall"},
+			expectedHeight: qty(182.9, "cm"),
+			expectedText:   "height 6'",
+		},
+		{
+			desc:          "inches of twelve or more are rejected",
+			clinicalNote:  &model.ClinicalNote{Text: "height 5'14\""},
+			expectedError: errors.New("unable to parse given height of height 5'14\": inches remainder of 14 is not less than a foot"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedHeight, healthMetric.Height)
+			require.Len(t, healthMetric.Observations, 1)
+			assert.Equal(t, tt.expectedText, healthMetric.Observations[0].Text)
+		})
+	}
+}
+
+func TestParserService_ParseClinicalNote_ForStonesWeight(t *testing.T) {
+	tests := []struct {
+		desc           string
+		clinicalNote   *model.ClinicalNote
+		expectedWeight *quantity.Quantity
+		expectedText   string
+		expectedError  error
+	}{
+		{
+			desc:           "stones abbreviated",
+			clinicalNote:   &model.ClinicalNote{Text: "wt 12 st on clinic scales"},
+			expectedWeight: qty(76.2, "kg"),
+			expectedText:   "wt 12 st",
+		},
+		{
+			desc:           "stone singular",
+			clinicalNote:   &model.ClinicalNote{Text: "weighs 1 stone"},
+			expectedWeight: qty(6.35, "kg"),
+			expectedText:   "weighs 1 stone",
+		},
+		{
+			desc:           "stones plural with decimal",
+			clinicalNote:   &model.ClinicalNote{Text: "weight of 10.5 stones"},
+			expectedWeight: qty(66.68, "kg"),
+			expectedText:   "weight of 10.5 stones",
+		},
+		{
+			desc:           "stones and pounds",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 12 st 4 lb"},
+			expectedWeight: qty(78.02, "kg"),
+			expectedText:   "weight 12 st 4 lb",
+		},
+		{
+			desc:           "stones and pounds run together",
+			clinicalNote:   &model.ClinicalNote{Text: "Wt: 12st4lbs, BP stable"},
+			expectedWeight: qty(78.02, "kg"),
+			expectedText:   "Wt: 12st4lbs",
+		},
+		{
+			desc:           "stone with bare pounds remainder",
+			clinicalNote:   &model.ClinicalNote{Text: "weighs 12 stone 4"},
+			expectedWeight: qty(78.02, "kg"),
+			expectedText:   "weighs 12 stone 4",
+		},
+		{
+			desc:          "pounds remainder of fourteen or more is rejected",
+			clinicalNote:  &model.ClinicalNote{Text: "weight 12 st 15 lb"},
+			expectedError: errors.New("unable to parse given weight of weight 12 st 15 lb: pounds remainder of 15 is not less than a stone"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			require.Len(t, healthMetric.Observations, 1)
+			assert.Equal(t, tt.expectedText, healthMetric.Observations[0].Text)
+		})
+	}
+}
+
+func TestParserService_ParseClinicalNote_ForGramsOuncesAndBirthWeight(t *testing.T) {
+	tests := []struct {
+		desc                string
+		clinicalNote        *model.ClinicalNote
+		expectedWeight      *quantity.Quantity
+		expectedBirthWeight *quantity.Quantity
+		expectedError       error
+	}{
+		{
+			desc:           "weight in grams",
+			clinicalNote:   &model.ClinicalNote{Text: "wt 3450 grams"},
+			expectedWeight: qty(3.45, "kg"),
+		},
+		{
+			desc:           "weight in ounces",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 120 oz"},
+			expectedWeight: qty(3.4, "kg"),
+		},
+		{
+			desc:           "pounds and ounces",
+			clinicalNote:   &model.ClinicalNote{Text: "weight 7 lb 8 oz"},
+			expectedWeight: qty(3.4, "kg"),
+		},
+		{
+			desc:                "birth weight in grams",
+			clinicalNote:        &model.ClinicalNote{Text: "birth weight 3450 g"},
+			expectedBirthWeight: qty(3.45, "kg"),
+		},
+		{
+			desc:                "birth weight abbreviated",
+			clinicalNote:        &model.ClinicalNote{Text: "term baby, BW 3.45kg"},
+			expectedBirthWeight: qty(3.45, "kg"),
+		},
+		{
+			desc:                "birth weight in pounds and ounces keeps the gram",
+			clinicalNote:        &model.ClinicalNote{Text: "birth wt 7lbs 8oz"},
+			expectedBirthWeight: qty(3.402, "kg"),
+		},
+		{
+			desc:                "birth weight is never the current weight",
+			clinicalNote:        &model.ClinicalNote{Text: "6 week old, birth weight 3450 g, current weight 4.8 kg"},
+			expectedWeight:      qty(4.8, "kg"),
+			expectedBirthWeight: qty(3.45, "kg"),
+		},
+		{
+			desc:                "abbreviated birth weight of a neonate by age",
+			clinicalNote:        &model.ClinicalNote{Text: "BW 3.45kg", AgeDays: func(d int) *int { return &d }(3)},
+			expectedBirthWeight: qty(3.45, "kg"),
+		},
+		{
+			desc:           "BW of an adult is the body weight",
+			clinicalNote:   &model.ClinicalNote{Text: "adult male, BW 80 kg, height 180cm"},
+			expectedWeight: qty(80, "kg"),
+		},
+		{
+			desc:           "BW without an age or neonatal context is the body weight",
+			clinicalNote:   &model.ClinicalNote{Text: "BW 80 kg"},
+			expectedWeight: qty(80, "kg"),
+		},
+		{
+			desc:                "birth weight of an adult is checked as a neonate",
+			clinicalNote:        &model.ClinicalNote{Text: "aged 45, weight 80kg, birth weight 2.1 kg"},
+			expectedWeight:      qty(80, "kg"),
+			expectedBirthWeight: qty(2.1, "kg"),
+		},
+		{
+			desc:         "implausible birth weight held back for review",
+			clinicalNote: &model.ClinicalNote{Text: "birth weight 34 kg"},
+		},
+		{
+			desc:          "ounces remainder of sixteen or more is rejected",
+			clinicalNote:  &model.ClinicalNote{Text: "weight 7 lb 16 oz"},
+			expectedError: errors.New("unable to parse given weight of weight 7 lb 16 oz: ounces remainder of 16 is not less than a pound"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			assert.Equal(t, tt.expectedBirthWeight, healthMetric.BirthWeight)
+		})
+	}
+}
+
+func newTestParserService(t *testing.T) *service.ParserService {
+	t.Helper()
+	testService, err := service.NewParserService(testsupport.Logger(), service.Config{}, service.DefaultExtractorRegistry())
+	require.NoError(t, err)
+
+	return testService
+}
+
+func qty(value float64, unit string) *quantity.Quantity {
+	return &quantity.Quantity{Value: value, Unit: unit}
+}
+
+// primaryMetric drops the observation list so table tests can assert on the primary fields alone.
+func primaryMetric(m *model.HealthMetric) *model.HealthMetric {
+	if m == nil {
+		return nil
+	}
+	return &model.HealthMetric{Weight: m.Weight, Height: m.Height}
+}
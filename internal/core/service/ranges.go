@@ -0,0 +1,30 @@
+package service
+
+import "cleo.com/internal/core/domain/model"
+
+// withoutRanges drops the observations written as a range, for requests that reject ranges.
+func withoutRanges(observations []model.Observation) []model.Observation {
+	var kept []model.Observation
+	for _, o := range observations {
+		if o.Range == nil {
+			kept = append(kept, o)
+		}
+	}
+
+	return kept
+}
+
+// rangeStatus explains a missing primary value of the given kind as a rejected range when the note gave a range
+// and said nothing else about the measurement.
+func rangeStatus(status string, selected *model.Observation, observations []model.Observation, kind string) string {
+	if status != "" || selected != nil {
+		return status
+	}
+	for _, o := range observations {
+		if o.Kind == kind && o.Range != nil && isPrimaryCandidate(o) {
+			return model.StatusRangeRejected
+		}
+	}
+
+	return status
+}
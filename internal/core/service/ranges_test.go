@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForRangesAndApproximations(t *testing.T) {
+	tests := []struct {
+		desc           string
+		clinicalNote   *model.ClinicalNote
+		expectedMetric *model.HealthMetric
+		expectedError  error
+	}{
+		{
+			desc:         "weight range is reported as its midpoint",
+			clinicalNote: &model.ClinicalNote{Text: "weight 70-72kg"},
+			expectedMetric: &model.HealthMetric{
+				Weight:      qty(71, "kg"),
+				WeightRange: &model.Range{Low: quantity.Quantity{Value: 70, Unit: "kg"}, High: quantity.Quantity{Value: 72, Unit: "kg"}},
+			},
+		},
+		{
+			desc:         "range written with to",
+			clinicalNote: &model.ClinicalNote{Text: "weight 70 to 72 kg"},
+			expectedMetric: &model.HealthMetric{
+				Weight:      qty(71, "kg"),
+				WeightRange: &model.Range{Low: quantity.Quantity{Value: 70, Unit: "kg"}, High: quantity.Quantity{Value: 72, Unit: "kg"}},
+			},
+		},
+		{
+			desc:         "height range with an en dash",
+			clinicalNote: &model.ClinicalNote{Text: "height 170–175 cm"},
+			expectedMetric: &model.HealthMetric{
+				Height:      qty(172.5, "cm"),
+				HeightRange: &model.Range{Low: quantity.Quantity{Value: 170, Unit: "cm"}, High: quantity.Quantity{Value: 175, Unit: "cm"}},
+			},
+		},
+		{
+			desc:         "approximate weight is flagged as estimated",
+			clinicalNote: &model.ClinicalNote{Text: "weight approx 80kg"},
+			expectedMetric: &model.HealthMetric{
+				Weight:          qty(80, "kg"),
+				WeightEstimated: true,
+			},
+		},
+		{
+			desc:         "tilde marks an estimated height",
+			clinicalNote: &model.ClinicalNote{Text: "ht ~6ft"},
+			expectedMetric: &model.HealthMetric{
+				Height:          qty(182.9, "cm"),
+				HeightEstimated: true,
+			},
+		},
+		{
+			desc:         "approximate range in imperial units",
+			clinicalNote: &model.ClinicalNote{Text: "weight about 150-160 lb", Units: model.UnitSystemImperial},
+			expectedMetric: &model.HealthMetric{
+				Weight:          qty(155, "[lb_av]"),
+				WeightRange:     &model.Range{Low: quantity.Quantity{Value: 150, Unit: "[lb_av]"}, High: quantity.Quantity{Value: 160, Unit: "[lb_av]"}},
+				WeightEstimated: true,
+			},
+		},
+		{
+			desc:         "exact value outranks an approximate one",
+			clinicalNote: &model.ClinicalNote{Text: "weight approx 80kg; weight 82kg"},
+			expectedMetric: &model.HealthMetric{
+				Weight: qty(82, "kg"),
+			},
+		},
+		{
+			desc:         "rejected range leaves no weight",
+			clinicalNote: &model.ClinicalNote{Text: "weight 70-72kg", Ranges: model.RangePolicyReject},
+			expectedMetric: &model.HealthMetric{
+				WeightStatus: model.StatusRangeRejected,
+			},
+		},
+		{
+			desc:         "rejected range falls back to a single value",
+			clinicalNote: &model.ClinicalNote{Text: "weight 70-72kg; weight 71.5kg", Ranges: model.RangePolicyReject},
+			expectedMetric: &model.HealthMetric{
+				Weight: qty(71.5, "kg"),
+			},
+		},
+		{
+			desc:          "decreasing range",
+			clinicalNote:  &model.ClinicalNote{Text: "weight 72-70kg"},
+			expectedError: errors.New("unable to parse given weight of weight 72-70kg: range of 72 to 70 does not increase"),
+		},
+		{
+			desc:          "implausible upper bound",
+			clinicalNote:  &model.ClinicalNote{Text: "height 100-300cm"},
+			expectedError: errors.New("invalid height of 300 cm for age band unknown"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMetric, rangeMetric(healthMetric))
+		})
+	}
+}
+
+// rangeMetric keeps the weight and height fields that ranges and approximations affect.
+func rangeMetric(m *model.HealthMetric) *model.HealthMetric {
+	return &model.HealthMetric{
+		Weight:          m.Weight,
+		Height:          m.Height,
+		WeightRange:     m.WeightRange,
+		HeightRange:     m.HeightRange,
+		WeightEstimated: m.WeightEstimated,
+		HeightEstimated: m.HeightEstimated,
+		WeightStatus:    m.WeightStatus,
+		HeightStatus:    m.HeightStatus,
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"cleo.com/internal/core/domain/model"
+)
+
+// contextCue is a phrase near a metric mention that tells us how authoritative the value is. A cue that
+// excludes says the value is not a measurement at all, so it is never chosen as the primary value.
+type contextCue struct {
+	name     string
+	pattern  *regexp.Regexp
+	score    int
+	excludes bool
+}
+
+// baselineScore is given to mentions that carry no context cue at all, e.g. "Wt: 80kg" in a vitals list.
+const baselineScore = 2
+
+var (
+	contextCues = []contextCue{
+		{name: "today", pattern: regexp.MustCompile(`(?i)\b(?:today|this morning|currently|current)\b`), score: 4},
+		{name: "measured", pattern: regexp.MustCompile(`(?i)\b(?:measured|weighed|found to be|recorded|on (?:the )?scales?)\b`), score: 3},
+		{name: "on admission", pattern: regexp.MustCompile(`(?i)\b(?:on admission|admission|admitted)\b`), score: 3},
+		{name: "reported", pattern: regexp.MustCompile(`(?i)\b(?:stated|states|self[- ]?reported|reported|reports|says|per patient)\b`), score: 1},
+		{name: "estimated", pattern: regexp.MustCompile(`(?i)\b(?:estimated|est|guessed|approx(?:imately)?|about|around|roughly|circa)\b|~|≈`), score: -1},
+		{name: "previously", pattern: regexp.MustCompile(`(?i)\b(?:previous(?:ly)?|prior|formerly|used to be|last (?:visit|year|month|week))\b`), score: -2},
+		{name: "target", pattern: regexp.MustCompile(`(?i)\b(?:target|goal|ideal|aim)\b`), score: -4, excludes: true},
+	}
+
+	// clauseBoundaryRegex splits a note into the clauses that cues are allowed to influence.
+	clauseBoundaryRegex = regexp.MustCompile(`(?i)[.!?;]\s|[;\n]|\b(?:but|however|whereas|although|while)\b`)
+	// trailingBoundaryRegex additionally stops trailing context at a comma so a cue is not borrowed from the next clause.
+	trailingBoundaryRegex = regexp.MustCompile(`(?i)[.!?;,]\s|[;,\n]|\b(?:but|however|whereas|although|while)\b`)
+)
+
+// rankObservations scores every observation on the cue words in the clause around it, adjusted by the priority
+// of the section it appears under. A date placed in time is left out when looking for cues against the value, as
+// its recency is weighed when choosing between dated values.
+func rankObservations(text string, observations []model.Observation, dates []dateMention) {
+	spans := observationSpans(observations)
+	for i := range observations {
+		o := &observations[i]
+		from, to := contextBounds(text, o.Start, o.End, spans, clauseBoundaryRegex)
+		window, undated := text[from:to], withoutDates(text, from, to, dates)
+		o.Cues = nil
+		o.Score = 0
+		for _, cue := range contextCues {
+			scope := window
+			if cue.score < 0 {
+				scope = undated
+			}
+			if cue.pattern.MatchString(scope) {
+				o.Cues = append(o.Cues, cue.name)
+				o.Score += cue.score
+			}
+		}
+		if len(o.Cues) == 0 {
+			o.Score = baselineScore
+		}
+		if priority := sectionWeightings[o.Section].priority; priority != 0 {
+			o.Cues = append(o.Cues, strings.ReplaceAll(o.Section, "_", " ")+" section")
+			o.Score += priority
+		}
+	}
+}
+
+// observationSpans returns the byte spans of every observation, ordered by position in the note.
+func observationSpans(observations []model.Observation) [][2]int {
+	spans := make([][2]int, len(observations))
+	for i, o := range observations {
+		spans[i] = [2]int{o.Start, o.End}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	return spans
+}
+
+// contextWindow returns the text around the mention, starting after the last leading boundary and stopping at
+// the first trailing boundary, cut short at any neighbouring mention.
+func contextWindow(text string, start, end int, spans [][2]int, leading *regexp.Regexp) string {
+	from, to := contextBounds(text, start, end, spans, leading)
+
+	return text[from:to]
+}
+
+// contextBounds returns the offsets of the context window around the mention.
+func contextBounds(text string, start, end int, spans [][2]int, leading *regexp.Regexp) (int, int) {
+	from, to := 0, len(text)
+	for _, span := range spans {
+		if span[1] <= start && span[1] > from {
+			from = span[1]
+		}
+		if span[0] >= end && span[0] < to {
+			to = span[0]
+		}
+	}
+	if bs := leading.FindAllStringIndex(text[from:start], -1); len(bs) > 0 {
+		from += bs[len(bs)-1][1]
+	}
+	if b := trailingBoundaryRegex.FindStringIndex(text[end:to]); b != nil {
+		to = end + b[0]
+	}
+
+	return from, to
+}
+
+// selectPrimary picks the most authoritative current observation of the given kind, preferring the earliest
+// mention when candidates tie, and explains why it was chosen. Failing any, a single dated historical
+// observation is reported, as the only measurement the note gives a date for.
+func selectPrimary(observations []model.Observation, kind string) (*model.Observation, string) {
+	var (
+		selected   *model.Observation
+		historical []*model.Observation
+		candidates int
+		dated      []time.Time
+		tied       bool
+	)
+	for i := range observations {
+		o := &observations[i]
+		if o.Kind != kind {
+			continue
+		}
+		if o.Historical && o.EffectiveTime != nil {
+			withoutHistory := *o
+			withoutHistory.Historical = false
+			if isPrimaryCandidate(withoutHistory) {
+				historical = append(historical, o)
+			}
+		}
+		if !isPrimaryCandidate(*o) {
+			continue
+		}
+		candidates++
+		if o.EffectiveTime != nil {
+			dated = append(dated, *o.EffectiveTime)
+		}
+		switch {
+		case selected == nil || outranks(*o, *selected):
+			selected, tied = o, false
+		case !outranks(*selected, *o):
+			tied = true
+		}
+	}
+	if selected == nil && len(historical) == 1 {
+		return historical[0], "only candidate, dated " + historical[0].EffectiveTime.Format(time.DateOnly)
+	}
+	if selected == nil {
+		return nil, ""
+	}
+
+	return selected, rankingReason(*selected, candidates, dated, tied)
+}
+
+// isPrimaryCandidate excludes observations that do not describe a current measurement of the patient, that are
+// held back for review, or that are a target or sit under a section, such as the plan, that never holds one.
+func isPrimaryCandidate(o model.Observation) bool {
+	return isCurrent(o) && !sectionWeightings[o.Section].excludes && !hasExcludingCue(o)
+}
+
+// isCurrent excludes observations that are negated, historical, held back for review or not of the patient.
+func isCurrent(o model.Observation) bool {
+	return !o.Negated && !o.Historical && !o.NeedsReview && isPatient(o)
+}
+
+func isPatient(o model.Observation) bool {
+	return o.Subject == "" || o.Subject == model.SubjectPatient
+}
+
+// outranks reports whether a should be preferred over b: certain values before uncertain ones, then the more
+// recent of two measurements dated differently, then by score. An undated value is taken as measured when the
+// note was written, so it is preferred over one dated earlier than today. A value with a negative cue, such as
+// an estimate, is never preferred for its date.
+func outranks(a, b model.Observation) bool {
+	if a.Uncertain != b.Uncertain {
+		return !a.Uncertain
+	}
+	if byRecency(a, b) {
+		return a.EffectiveTime.After(*b.EffectiveTime)
+	}
+	if isUndatedOverEarlier(a, b) || isUndatedOverEarlier(b, a) {
+		return a.EffectiveTime == nil
+	}
+
+	return a.Score > b.Score
+}
+
+// isUndatedOverEarlier reports whether undated is current beside a value dated before the day of writing.
+func isUndatedOverEarlier(undated, dated model.Observation) bool {
+	return undated.EffectiveTime == nil && dated.EffectiveTime != nil && !slices.Contains(dated.Cues, "today") &&
+		!hasNegativeCue(undated) && !hasNegativeCue(dated)
+}
+
+func byRecency(a, b model.Observation) bool {
+	return a.EffectiveTime != nil && b.EffectiveTime != nil && !a.EffectiveTime.Equal(*b.EffectiveTime) &&
+		!hasNegativeCue(a) && !hasNegativeCue(b)
+}
+
+func hasExcludingCue(o model.Observation) bool {
+	for _, cue := range contextCues {
+		if cue.excludes && slices.Contains(o.Cues, cue.name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasNegativeCue(o model.Observation) bool {
+	for _, cue := range contextCues {
+		if cue.score < 0 && slices.Contains(o.Cues, cue.name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func rankingReason(selected model.Observation, candidates int, dated []time.Time, tied bool) string {
+	if candidates == 1 {
+		return "only candidate"
+	}
+	cues := "no context cues"
+	if len(selected.Cues) > 0 {
+		cues = strings.Join(selected.Cues, ", ")
+	}
+	reason := fmt.Sprintf("ranked highest of %d candidates (%s)", candidates, cues)
+	if selected.EffectiveTime != nil && len(dated) > 1 && isMostRecent(*selected.EffectiveTime, dated) {
+		reason += fmt.Sprintf("; most recent of %d dated candidates", len(dated))
+	}
+	if tied {
+		reason += "; earliest of tied candidates"
+	}
+
+	return reason
+}
+
+func isMostRecent(at time.Time, dated []time.Time) bool {
+	for _, other := range dated {
+		if other.After(at) {
+			return false
+		}
+	}
+
+	return true
+}
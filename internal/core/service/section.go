@@ -0,0 +1,115 @@
+package service
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"cleo.com/internal/core/domain/model"
+)
+
+// sectionHeadingRegex matches a heading at the start of a line, either followed by a colon ("Vitals:", "PMH:",
+// "O:") or standing alone on its line ("FAMILY HISTORY").
+var sectionHeadingRegex = regexp.MustCompile(`(?im)^[ \t]*(vital signs|vitals?|observations?|obs|objective|examination|exam|o/e|subjective|history of presenting complaint|hpc|assessment|impression|past medical history|pmh|family history|fh|social history|sh|plan|[soap])[ \t]*(?::|$)`)
+
+// sectionNames maps each heading to its canonical section; the single letters are the SOAP headings.
+var sectionNames = map[string]string{
+	"vital": model.SectionVitals, "vitals": model.SectionVitals, "vital signs": model.SectionVitals,
+	"observation": model.SectionVitals, "observations": model.SectionVitals, "obs": model.SectionVitals,
+	"objective": model.SectionObjective, "o": model.SectionObjective,
+	"examination": model.SectionExamination, "exam": model.SectionExamination, "o/e": model.SectionExamination,
+	"subjective": model.SectionSubjective, "s": model.SectionSubjective,
+	"history of presenting complaint": model.SectionSubjective, "hpc": model.SectionSubjective,
+	"assessment": model.SectionAssessment, "impression": model.SectionAssessment, "a": model.SectionAssessment,
+	"past medical history": model.SectionPastMedicalHistory, "pmh": model.SectionPastMedicalHistory,
+	"family history": model.SectionFamilyHistory, "fh": model.SectionFamilyHistory,
+	"social history": model.SectionSocialHistory, "sh": model.SectionSocialHistory,
+	"plan": model.SectionPlan, "p": model.SectionPlan,
+}
+
+// sectionWeighting says how far a measurement under a section can be trusted: reliability feeds confidence,
+// from 0 to 1, and priority is added to the ranking score when choosing the primary value. A section that
+// excludes never gives the primary value, as what it holds is not a measurement of the patient now.
+type sectionWeighting struct {
+	reliability float64
+	priority    int
+	excludes    bool
+}
+
+var sectionWeightings = map[string]sectionWeighting{
+	model.SectionVitals:             {reliability: 1, priority: 2},
+	model.SectionObjective:          {reliability: 1, priority: 2},
+	model.SectionExamination:        {reliability: 1, priority: 2},
+	model.SectionSubjective:         {reliability: 0.7, priority: 0},
+	model.SectionAssessment:         {reliability: 0.7, priority: 0},
+	model.SectionPastMedicalHistory: {reliability: 0.3, priority: -2},
+	model.SectionSocialHistory:      {reliability: 0.3, priority: -2},
+	model.SectionFamilyHistory:      {reliability: 0.2, priority: -4, excludes: true},
+	model.SectionPlan:               {reliability: 0.2, priority: -4, excludes: true},
+	// text before the first heading, or a note without headings
+	"": {reliability: 0.8, priority: 0},
+}
+
+// minSOAPHeadings is how many different single-letter headings a note needs before one followed by text on its
+// line is read as a SOAP heading, so that "P: 72" in a list of vitals is a pulse rather than the plan.
+const minSOAPHeadings = 2
+
+// section is the span of a note from the start of one heading to the start of the next, or to the end of its
+// line for a heading followed by text.
+type section struct {
+	name  string
+	start int
+	end   int
+}
+
+// segmentSections splits a note at its headings. A heading alone on its line runs to the next heading, while one
+// followed by text, such as "FH: mother 60kg", covers only its line, after which the text falls back under the
+// last heading alone on its line. Text before the first heading is returned as an unnamed section, so that every
+// offset in the note falls in exactly one section.
+func segmentSections(text string) []section {
+	matches := sectionHeadingRegex.FindAllStringSubmatchIndex(text, -1)
+	soapLetters := map[string]bool{}
+	for _, m := range matches {
+		if m[3]-m[2] == 1 {
+			soapLetters[strings.ToLower(text[m[2]:m[3]])] = true
+		}
+	}
+
+	sections := []section{{start: 0}}
+	block := ""
+	for _, m := range matches {
+		lineEnd := len(text)
+		if i := strings.IndexByte(text[m[1]:], '\n'); i >= 0 {
+			lineEnd = m[1] + i
+		}
+		alone := strings.TrimSpace(text[m[1]:lineEnd]) == ""
+		if m[3]-m[2] == 1 && !alone && len(soapLetters) < minSOAPHeadings {
+			continue
+		}
+		sections[len(sections)-1].end = m[0]
+		heading := strings.ToLower(strings.Join(strings.Fields(text[m[2]:m[3]]), " "))
+		sections = append(sections, section{name: sectionNames[heading], start: m[0]})
+		if alone {
+			block = sectionNames[heading]
+			continue
+		}
+		if lineEnd < len(text) {
+			sections[len(sections)-1].end = lineEnd
+			sections = append(sections, section{name: block, start: lineEnd})
+		}
+	}
+	sections[len(sections)-1].end = len(text)
+
+	return sections
+}
+
+// tagSections sets the section of every observation from where it starts in the note.
+func tagSections(sections []section, observations []model.Observation) {
+	for i := range observations {
+		o := &observations[i]
+		j := sort.Search(len(sections), func(j int) bool { return sections[j].end > o.Start })
+		if j < len(sections) {
+			o.Section = sections[j].name
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForSections(t *testing.T) {
+	tests := []struct {
+		desc             string
+		clinicalNote     *model.ClinicalNote
+		expectedSections []string
+		expectedWeight   *quantity.Quantity
+		expectedReason   string
+	}{
+		{
+			desc:             "note without headings",
+			clinicalNote:     &model.ClinicalNote{Text: "weight 82kg"},
+			expectedSections: []string{""},
+			expectedWeight:   qty(82, "kg"),
+			expectedReason:   "only candidate",
+		},
+		{
+			desc: "SOAP note with structured headings",
+			clinicalNote: &model.ClinicalNote{Text: "S: feels well\nO:\nVitals: HR 80, wt 82kg\n" +
+				"Family History: father weight 120kg\nPlan: target weight 70kg"},
+			expectedSections: []string{model.SectionVitals, model.SectionFamilyHistory, model.SectionPlan, model.SectionVitals},
+			expectedWeight:   qty(82, "kg"),
+			expectedReason:   "only candidate",
+		},
+		{
+			desc:             "target weight in the plan is never the weight",
+			clinicalNote:     &model.ClinicalNote{Text: "Plan: target weight 70kg"},
+			expectedSections: []string{model.SectionPlan},
+		},
+		{
+			desc:             "weight under family history is never the weight",
+			clinicalNote:     &model.ClinicalNote{Text: "Family History:\nobesity, weight 130kg"},
+			expectedSections: []string{model.SectionFamilyHistory},
+		},
+		{
+			desc:             "target weight without a section is never the weight",
+			clinicalNote:     &model.ClinicalNote{Text: "target weight 70kg"},
+			expectedSections: []string{""},
+		},
+		{
+			desc:             "vitals outrank past medical history",
+			clinicalNote:     &model.ClinicalNote{Text: "PMH: obesity, weight 95kg\nVitals: weight 82kg"},
+			expectedSections: []string{model.SectionPastMedicalHistory, model.SectionVitals},
+			expectedWeight:   qty(82, "kg"),
+			expectedReason:   "ranked highest of 2 candidates (vitals section)",
+		},
+		{
+			desc:             "headings alone on their line",
+			clinicalNote:     &model.ClinicalNote{Text: "FAMILY HISTORY\nmother weight 110kg\nOBSERVATIONS\nweight 70kg"},
+			expectedSections: []string{model.SectionFamilyHistory, model.SectionVitals},
+			expectedWeight:   qty(70, "kg"),
+			expectedReason:   "only candidate",
+		},
+		{
+			desc:             "pulse in a list of vitals is not the plan",
+			clinicalNote:     &model.ClinicalNote{Text: "Obs: BP 120/80\nP: 72\nWt: 80kg"},
+			expectedSections: []string{"", model.SectionVitals, model.SectionVitals},
+			expectedWeight:   qty(80, "kg"),
+			expectedReason:   "only candidate",
+		},
+		{
+			desc:             "heading followed by text covers only its line",
+			clinicalNote:     &model.ClinicalNote{Text: "O/E\nFH: mother weight 60kg\nweight 80kg"},
+			expectedSections: []string{model.SectionFamilyHistory, model.SectionExamination},
+			expectedWeight:   qty(80, "kg"),
+			expectedReason:   "only candidate",
+		},
+		{
+			desc:             "examination outranks a stated weight earlier in the note",
+			clinicalNote:     &model.ClinicalNote{Text: "HPC: reports weight 90kg\nO/E: weight 86kg"},
+			expectedSections: []string{model.SectionSubjective, model.SectionExamination},
+			expectedWeight:   qty(86, "kg"),
+			expectedReason:   "ranked highest of 2 candidates (examination section)",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			var sections []string
+			for _, o := range healthMetric.Observations {
+				sections = append(sections, o.Section)
+			}
+			assert.Equal(t, tt.expectedSections, sections)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			assert.Equal(t, tt.expectedReason, healthMetric.WeightReason)
+		})
+	}
+}
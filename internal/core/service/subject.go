@@ -0,0 +1,92 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"cleo.com/internal/core/domain/model"
+)
+
+type subjectCue struct {
+	subject string
+	pattern *regexp.Regexp
+}
+
+// subjectCues name who a measurement belongs to. "twin" is left out, as in a neonatal note the twin is usually
+// the patient.
+var subjectCues = []subjectCue{
+	{subject: model.SubjectPatient, pattern: regexp.MustCompile(`(?i)\b(?:patient|pt|baby|infant|child)(?:'s)?\b`)},
+	{subject: model.SubjectMother, pattern: regexp.MustCompile(`(?i)\b(?:mother|mum|mom|mummy|mommy|maternal)(?:'s)?\b`)},
+	{subject: model.SubjectFather, pattern: regexp.MustCompile(`(?i)\b(?:father|dad|daddy|paternal)(?:'s)?\b`)},
+	{subject: model.SubjectSibling, pattern: regexp.MustCompile(`(?i)\b(?:sibling|brother|sister)(?:'s)?\b`)},
+	{subject: model.SubjectFetus, pattern: regexp.MustCompile(`(?i)\b(?:fetus|foetus|fetal|foetal|efw|in utero|unborn)\b`)},
+	{subject: model.SubjectDonor, pattern: regexp.MustCompile(`(?i)\bdonor(?:'s)?\b`)},
+}
+
+// speakerRegex matches a reporting verb after a person named in the note, as in "Mum says", which makes them
+// the speaker rather than the subject of what follows.
+var speakerRegex = regexp.MustCompile(`(?i)^\s+(?:says|said|reports|reported|states|stated|tells|told|thinks|feels|believes)\b`)
+
+// subjectIsPattern introduces a value given directly for a person, as in "patient is 45kg" or "mum was 60kg". It
+// stands in for a weight keyword whatever the lexicon, and only a value with a weight unit after it is read, so
+// "patient is 80 years old" is not.
+const subjectIsPattern = `(?:patient|pt|baby|infant|child|mother|mum|mom|father|dad|sibling|brother|sister|donor)(?:'s)?\s+(?:is|was)`
+
+// fetalAgeBand bounds a fetal weight and length from the end of the first trimester to term.
+var fetalAgeBand = ageBand{name: "fetal", weightKg: [2]float64{0.05, 7}, heightCm: [2]float64{5, 65}}
+
+// attributeSubjects sets who each observation belongs to from the last subject named before or within it in its
+// phrase, or failing that the first named after it, so that "mother weighs 60kg, patient is 45kg" gives the 60kg
+// to the mother. A person reporting, as in "Mum says weight 12kg", is not the subject, and an observation with no
+// subject named belongs to the patient.
+func attributeSubjects(text string, observations []model.Observation) {
+	spans := observationSpans(observations)
+	for i := range observations {
+		o := &observations[i]
+		o.Subject = subjectOf(text, o.Start, o.End, spans)
+	}
+}
+
+// subjectOf returns who the mention at start:end describes, cutting its phrase short at the given spans of other
+// mentions.
+func subjectOf(text string, start, end int, spans [][2]int) string {
+	from, to := contextBounds(text, start, end, spans, trailingBoundaryRegex)
+	subject := model.SubjectPatient
+	before, after := -1, to
+	for _, cue := range subjectCues {
+		for _, m := range cue.pattern.FindAllStringIndex(text[from:to], -1) {
+			if isSpeaker(text, from+m[0], from+m[1]) {
+				continue
+			}
+			at := from + m[0]
+			switch {
+			case at < end && at > before:
+				before = at
+				subject = cue.subject
+			case before < 0 && at >= end && at < after:
+				after = at
+				subject = cue.subject
+			}
+		}
+	}
+
+	return subject
+}
+
+// isSpeaker reports whether the person named at start:end is reporting what follows rather than its subject.
+func isSpeaker(text string, start, end int) bool {
+	return !strings.HasSuffix(strings.ToLower(text[start:end]), "'s") && speakerRegex.MatchString(text[end:])
+}
+
+// subjectAgeBand returns the band the observation is judged against: the patient's, the fetal band, or for any
+// other person the fixed windows used when the age is unknown.
+func subjectAgeBand(o model.Observation, band ageBand) ageBand {
+	switch o.Subject {
+	case "", model.SubjectPatient:
+		return band
+	case model.SubjectFetus:
+		return fetalAgeBand
+	default:
+		return unknownAgeBand
+	}
+}
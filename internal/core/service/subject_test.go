@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForSubjects(t *testing.T) {
+	tests := []struct {
+		desc             string
+		clinicalNote     *model.ClinicalNote
+		expectedSubjects []string
+		expectedWeight   *quantity.Quantity
+		expectedHeight   *quantity.Quantity
+	}{
+		{
+			desc:             "family member named before the patient",
+			clinicalNote:     &model.ClinicalNote{Text: "Mother weighs 60kg, patient is 45kg"},
+			expectedSubjects: []string{model.SubjectMother, model.SubjectPatient},
+			expectedWeight:   qty(45, "kg"),
+		},
+		{
+			desc:             "patient's relative is the relative",
+			clinicalNote:     &model.ClinicalNote{Text: "patient's father height 185cm, height 110cm"},
+			expectedSubjects: []string{model.SubjectFather, model.SubjectPatient},
+			expectedHeight:   qty(110, "cm"),
+		},
+		{
+			desc:             "adult weight in a neonatal note is not judged against the neonate",
+			clinicalNote:     &model.ClinicalNote{Text: "mum's weight 82kg; baby weight 3.2kg", AgeDays: func(d int) *int { return &d }(5)},
+			expectedSubjects: []string{model.SubjectMother, model.SubjectPatient},
+			expectedWeight:   qty(3.2, "kg"),
+		},
+		{
+			desc:             "fetal weight below the range of a born patient",
+			clinicalNote:     &model.ClinicalNote{Text: "fetal weight 0.8kg; maternal weight 70kg"},
+			expectedSubjects: []string{model.SubjectFetus, model.SubjectMother},
+		},
+		{
+			desc:             "subject named after the measurement",
+			clinicalNote:     &model.ClinicalNote{Text: "weight 80kg (donor), weight 75kg"},
+			expectedSubjects: []string{model.SubjectDonor, model.SubjectPatient},
+			expectedWeight:   qty(75, "kg"),
+		},
+		{
+			desc:             "sibling",
+			clinicalNote:     &model.ClinicalNote{Text: "sister weighs 30kg"},
+			expectedSubjects: []string{model.SubjectSibling},
+		},
+		{
+			desc:             "age of the patient is not a weight",
+			clinicalNote:     &model.ClinicalNote{Text: "patient is 80 years old, weight 80kg"},
+			expectedSubjects: []string{model.SubjectPatient},
+			expectedWeight:   qty(80, "kg"),
+		},
+		{
+			desc:             "parent reporting the baby's weight",
+			clinicalNote:     &model.ClinicalNote{Text: "Mum says baby weighs 3.2kg"},
+			expectedSubjects: []string{model.SubjectPatient},
+			expectedWeight:   qty(3.2, "kg"),
+		},
+		{
+			desc:             "parent reporting the weight",
+			clinicalNote:     &model.ClinicalNote{Text: "Mum reports weight 12kg"},
+			expectedSubjects: []string{model.SubjectPatient},
+			expectedWeight:   qty(12, "kg"),
+		},
+		{
+			desc:             "parent reporting the weight of a child with an age",
+			clinicalNote:     &model.ClinicalNote{Text: "Dad says weight 14 kg, 3 year old"},
+			expectedSubjects: []string{model.SubjectPatient},
+			expectedWeight:   qty(14, "kg"),
+		},
+		{
+			desc:             "parent's reported weight is the parent's",
+			clinicalNote:     &model.ClinicalNote{Text: "mum's reported weight 60kg, baby weight 3.2kg"},
+			expectedSubjects: []string{model.SubjectMother, model.SubjectPatient},
+			expectedWeight:   qty(3.2, "kg"),
+		},
+		{
+			desc:             "patient by default",
+			clinicalNote:     &model.ClinicalNote{Text: "wt 80kg, ht 180cm"},
+			expectedSubjects: []string{model.SubjectPatient, model.SubjectPatient},
+			expectedWeight:   qty(80, "kg"),
+			expectedHeight:   qty(180, "cm"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			var subjects []string
+			for _, o := range healthMetric.Observations {
+				subjects = append(subjects, o.Subject)
+			}
+			assert.Equal(t, tt.expectedSubjects, subjects)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+			assert.Equal(t, tt.expectedHeight, healthMetric.Height)
+		})
+	}
+}
+
+func TestParserService_ParseClinicalNote_TakesAgeBandFromThePatient(t *testing.T) {
+	tests := []struct {
+		desc            string
+		clinicalNote    *model.ClinicalNote
+		expectedAgeBand string
+		expectedWeight  *quantity.Quantity
+	}{
+		{
+			desc:            "mother's age is not the baby's",
+			clinicalNote:    &model.ClinicalNote{Text: "mother aged 30, baby weight 3.4kg"},
+			expectedAgeBand: "unknown",
+			expectedWeight:  qty(3.4, "kg"),
+		},
+		{
+			desc:            "patient's age after a relative's",
+			clinicalNote:    &model.ClinicalNote{Text: "mother aged 30; patient aged 5 days, weight 3.4kg"},
+			expectedAgeBand: "neonate",
+			expectedWeight:  qty(3.4, "kg"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAgeBand, healthMetric.AgeBand)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+		})
+	}
+}
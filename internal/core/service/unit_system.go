@@ -0,0 +1,92 @@
+package service
+
+import (
+	"math"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+// outputUnits is the UCUM unit each convertible primary metric is reported in, per unit system.
+var outputUnits = map[string]map[string]string{
+	model.UnitSystemMetric: {
+		model.KindWeight:       "kg",
+		model.KindBirthWeight:  "kg",
+		model.KindWeightChange: "kg",
+		model.KindHeight:       "cm",
+		model.KindTemperature:  "Cel",
+	},
+	model.UnitSystemImperial: {
+		model.KindWeight:       "[lb_av]",
+		model.KindBirthWeight:  "[lb_av]",
+		model.KindWeightChange: "[lb_av]",
+		model.KindHeight:       "[in_i]",
+		model.KindTemperature:  "[degF]",
+	},
+}
+
+// outputPrecision is the number of decimal places each convertible primary metric is reported to.
+var outputPrecision = map[string]int{
+	model.KindWeight:       2,
+	model.KindBirthWeight:  3, // to the gram
+	model.KindWeightChange: 2,
+	model.KindHeight:       1,
+	model.KindTemperature:  1,
+}
+
+// inUnitSystem returns the value of a primary observation in the requested unit system, converting from the
+// value as written so that a note in pounds reports exactly the pounds it gave. As-written output keeps the
+// unit from the note.
+func inUnitSystem(o *model.Observation, system string) (*quantity.Quantity, error) {
+	if o == nil {
+		return nil, nil
+	}
+	source := o.Quantity()
+	if o.Written != nil {
+		source = *o.Written
+	}
+	q, err := source.To(outputUnit(o, system))
+	if err != nil {
+		return nil, err
+	}
+	q = q.Round(outputPrecision[o.Kind])
+
+	return &q, nil
+}
+
+// rangeInUnitSystem returns the bounds of a primary observation written as a range in the requested unit system,
+// or nil when it was a single value.
+func rangeInUnitSystem(o *model.Observation, system string) (*model.Range, error) {
+	if o == nil || o.Range == nil {
+		return nil, nil
+	}
+
+	return convertRange(o.Range, outputUnit(o, system), outputPrecision[o.Kind])
+}
+
+// outputUnit is the UCUM unit a primary observation is reported in; as-written output keeps the unit from the note.
+func outputUnit(o *model.Observation, system string) string {
+	if system != model.UnitSystemAsWritten {
+		return outputUnits[system][o.Kind]
+	}
+	if o.Written != nil {
+		return o.Written.Unit
+	}
+
+	return o.Unit
+}
+
+// feetAndInches splits an imperial height into whole feet and the remaining inches.
+func feetAndInches(height *quantity.Quantity) *model.FeetAndInches {
+	if height == nil || (height.Unit != "[in_i]" && height.Unit != "[ft_i]") {
+		return nil
+	}
+	inches, err := height.To("[in_i]")
+	if err != nil {
+		return nil
+	}
+	total := round(inches.Value, 1)
+	feet := math.Floor(total / 12)
+
+	return &model.FeetAndInches{Feet: int(feet), Inches: round(total-feet*12, 1)}
+}
@@ -0,0 +1,63 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForUnitSystem(t *testing.T) {
+	tests := []struct {
+		desc                     string
+		clinicalNote             *model.ClinicalNote
+		expectedUnitSystem       string
+		expectedMetric           *model.HealthMetric
+		expectedHeightFeetInches *model.FeetAndInches
+	}{
+		{
+			desc:               "metric by default",
+			clinicalNote:       &model.ClinicalNote{Text: "weight 165.34 lbs, height 5'9\", temp 98.6F"},
+			expectedUnitSystem: model.UnitSystemMetric,
+			expectedMetric:     &model.HealthMetric{Weight: qty(75, "kg"), Height: qty(175.3, "cm"), Temperature: qty(37, "Cel")},
+		},
+		{
+			desc:                     "imperial converts metric values",
+			clinicalNote:             &model.ClinicalNote{Text: "weight 80kg, height 180cm, temp 38.5C", Units: model.UnitSystemImperial},
+			expectedUnitSystem:       model.UnitSystemImperial,
+			expectedMetric:           &model.HealthMetric{Weight: qty(176.37, "[lb_av]"), Height: qty(70.9, "[in_i]"), Temperature: qty(101.3, "[degF]")},
+			expectedHeightFeetInches: &model.FeetAndInches{Feet: 5, Inches: 10.9},
+		},
+		{
+			desc:                     "imperial keeps imperial values exact",
+			clinicalNote:             &model.ClinicalNote{Text: "weight 165.34 lbs, height 5'9\"", Units: model.UnitSystemImperial},
+			expectedUnitSystem:       model.UnitSystemImperial,
+			expectedMetric:           &model.HealthMetric{Weight: qty(165.34, "[lb_av]"), Height: qty(69, "[in_i]")},
+			expectedHeightFeetInches: &model.FeetAndInches{Feet: 5, Inches: 9},
+		},
+		{
+			desc:                     "as written keeps the units from the note",
+			clinicalNote:             &model.ClinicalNote{Text: "wt 12 st 4 lb, height 6 ft, temp 37.2 C", Units: model.UnitSystemAsWritten},
+			expectedUnitSystem:       model.UnitSystemAsWritten,
+			expectedMetric:           &model.HealthMetric{Weight: qty(12.29, "[stone_av]"), Height: qty(6, "[ft_i]"), Temperature: qty(37.2, "Cel")},
+			expectedHeightFeetInches: &model.FeetAndInches{Feet: 6, Inches: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedUnitSystem, healthMetric.UnitSystem)
+			assert.Equal(t, tt.expectedMetric.Weight, healthMetric.Weight)
+			assert.Equal(t, tt.expectedMetric.Height, healthMetric.Height)
+			assert.Equal(t, tt.expectedMetric.Temperature, healthMetric.Temperature)
+			assert.Equal(t, tt.expectedHeightFeetInches, healthMetric.HeightFeetInches)
+		})
+	}
+}
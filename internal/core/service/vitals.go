@@ -0,0 +1,193 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+var (
+	heartRateRegex       = regexp.MustCompile(`(?i)\b(?:heart\s+rate|pulse(?:\s+rate)?|hr)\s*(?:of|is|was|at|:|=)?\s*(\d{2,3})(?:\s*(?:bpm|beats?\s*(?:per|/)\s*min(?:ute)?|/min))?\b`)
+	respiratoryRateRegex = regexp.MustCompile(`(?i)\b(?:resp(?:iratory)?\s+rate|resps|rr)\s*(?:of|is|was|at|:|=)?\s*(\d{1,2})(?:\s*(?:breaths?\s*(?:per|/)\s*min(?:ute)?|/min|bpm))?\b`)
+	// oxygenSaturationRegex captures the saturation and, when stated, either room air or the supplemental
+	// oxygen flow in litres per minute.
+	oxygenSaturationRegex = regexp.MustCompile(`(?i)\b(?:spo2|sp02|sao2|o2\s+sats?|sats|oxygen\s+saturation)\s*(?:of|is|was|at|:|=)?\s*(\d{2,3})\s*%?(?:\s*(?:on\s+)?(?:(ra|room\s+air|on\s+air)\b|(\d{1,2}(?:\.\d)?)\s*(?:l/min|lpm|litres?|liters?|l)\b(?:\s*(?:o2|oxygen)\b)?))?`)
+	// durationRegex matches a number just before a mention, which makes "hr" the hours of a duration, as in
+	// "6 hr 10 min".
+	durationRegex    = regexp.MustCompile(`\d\s*$`)
+	temperatureRegex = regexp.MustCompile(`(?i)\b(?:temperature|temp|tympanic)\s*(?:of|is|was|at|:|=)?\s*(\d{2,3}(?:\.\d{1,2})?)\s*(?:(°\s*c|°\s*f|º\s*c|º\s*f|degrees?\s+(?:celsius|fahrenheit|centigrade|c|f)|celsius|fahrenheit|centigrade|c|f)\b)?`)
+)
+
+// apply sensible medical ranges for vital signs
+const (
+	minHeartRateBpm          = 20.0
+	maxHeartRateBpm          = 300.0
+	minRespiratoryRatePerMin = 4.0
+	maxRespiratoryRatePerMin = 80.0
+	minOxygenSaturation      = 50.0
+	maxOxygenSaturation      = 100.0
+	maxOxygenFlowLPerMin     = 60.0 // high-flow nasal oxygen
+	minTemperatureC          = 25.0
+	maxTemperatureC          = 45.0
+	// fahrenheitThreshold separates unitless temperatures: no plausible body temperature is above 45 °C or
+	// below 77 °F.
+	fahrenheitThreshold = 50.0
+)
+
+// extractHeartRateMetrics returns every heart rate or pulse mention in the note, in order of appearance. A rate
+// outside the plausible range is marked implausible rather than failing the note.
+func extractHeartRateMetrics(text string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, m := range heartRateRegex.FindAllStringSubmatchIndex(text, -1) {
+		if strings.EqualFold(text[m[0]:min(m[0]+2, m[1])], "hr") && durationRegex.MatchString(text[:m[0]]) {
+			continue
+		}
+		valStr := text[m[2]:m[3]]
+		v, err := strconv.ParseFloat(valStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given heart rate of %s ", valStr)
+		}
+		observation := newObservation(text, m[0], m[1], model.KindHeartRate, quantity.Quantity{Value: v, Unit: "/min"})
+		observation.Implausible = !isValidHeartRate(v)
+		observations = append(observations, observation)
+	}
+
+	return observations, nil
+}
+
+// extractRespiratoryRateMetrics returns every respiratory rate mention in the note, in order of appearance.
+func extractRespiratoryRateMetrics(text string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, m := range respiratoryRateRegex.FindAllStringSubmatchIndex(text, -1) {
+		valStr := text[m[2]:m[3]]
+		v, err := strconv.ParseFloat(valStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given respiratory rate of %s ", valStr)
+		}
+		observation := newObservation(text, m[0], m[1], model.KindRespiratoryRate, quantity.Quantity{Value: v, Unit: "/min"})
+		observation.Implausible = !isValidRespiratoryRate(v)
+		observations = append(observations, observation)
+	}
+
+	return observations, nil
+}
+
+// extractOxygenSaturationMetrics returns every SpO2 mention in the note, in order of appearance. When the note
+// says what the patient was breathing, an oxygen flow observation shares the span, with room air as 0 L/min.
+// Implausible saturations and flows are marked rather than failing the note.
+func extractOxygenSaturationMetrics(text string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, m := range oxygenSaturationRegex.FindAllStringSubmatchIndex(text, -1) {
+		valStr := text[m[2]:m[3]]
+		v, err := strconv.ParseFloat(valStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given oxygen saturation of %s ", valStr)
+		}
+		observation := newObservation(text, m[0], m[1], model.KindOxygenSaturation, quantity.Quantity{Value: v, Unit: "%"})
+		observation.Implausible = !isValidOxygenSaturation(v)
+		observations = append(observations, observation)
+
+		switch {
+		case m[4] >= 0:
+			observations = append(observations, newObservation(text, m[0], m[1], model.KindOxygenFlow, quantity.Quantity{Value: 0, Unit: "L/min"}))
+		case m[6] >= 0:
+			flowStr := text[m[6]:m[7]]
+			flow, err := strconv.ParseFloat(flowStr, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse given oxygen flow of %s ", flowStr)
+			}
+			observation := newObservation(text, m[0], m[1], model.KindOxygenFlow, quantity.Quantity{Value: flow, Unit: "L/min"})
+			observation.Implausible = !isValidOxygenFlow(flow)
+			observations = append(observations, observation)
+		}
+	}
+
+	return observations, nil
+}
+
+// extractTemperatureMetrics returns every body temperature mention in the note in °C, in order of appearance.
+func extractTemperatureMetrics(text string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, m := range temperatureRegex.FindAllStringSubmatchIndex(text, -1) {
+		valStr := text[m[2]:m[3]]
+		v, err := strconv.ParseFloat(valStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given temperature of %s ", valStr)
+		}
+		unitStr := ""
+		if m[4] >= 0 {
+			unitStr = text[m[4]:m[5]]
+		}
+		unit, err := temperatureUnit(unitStr, v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse given temperature of %s: %w", text[m[0]:m[1]], err)
+		}
+		written := v
+		celsius, err := quantity.Quantity{Value: written, Unit: unit.Code}.To("Cel")
+		if err != nil {
+			return nil, err
+		}
+		observation := newObservation(text, m[0], m[1], model.KindTemperature, celsius.Round(1))
+		observation.Written = &quantity.Quantity{Value: written, Unit: unit.Code}
+		observation.Implausible = !isValidTemperature(celsius.Value)
+		observations = append(observations, observation)
+	}
+
+	return observations, nil
+}
+
+// selectOxygenSupport says what the patient was breathing for the primary SpO2 reading, with the flow when
+// supplemental oxygen was stated.
+func selectOxygenSupport(observations []model.Observation, saturation *model.Observation) (string, *quantity.Quantity) {
+	if saturation == nil {
+		return "", nil
+	}
+	for _, o := range observations {
+		if o.Kind != model.KindOxygenFlow || o.Start != saturation.Start || o.Implausible {
+			continue
+		}
+		if o.Value == 0 {
+			return model.OxygenRoomAir, nil
+		}
+		return model.OxygenSupplemental, quantityOf(&o)
+	}
+
+	return "", nil
+}
+
+// temperatureUnit falls back to the magnitude of the value when the note gives no unit.
+func temperatureUnit(spelling string, val float64) (quantity.Unit, error) {
+	if spelling != "" {
+		return quantity.Parse(spelling)
+	}
+	if val > fahrenheitThreshold {
+		return quantity.Lookup("[degF]")
+	}
+
+	return quantity.Lookup("Cel")
+}
+
+func isValidHeartRate(v float64) bool {
+	return v >= minHeartRateBpm && v <= maxHeartRateBpm && !math.IsNaN(v)
+}
+
+func isValidRespiratoryRate(v float64) bool {
+	return v >= minRespiratoryRatePerMin && v <= maxRespiratoryRatePerMin && !math.IsNaN(v)
+}
+
+func isValidOxygenSaturation(v float64) bool {
+	return v >= minOxygenSaturation && v <= maxOxygenSaturation && !math.IsNaN(v)
+}
+
+func isValidOxygenFlow(v float64) bool {
+	return v > 0 && v <= maxOxygenFlowLPerMin && !math.IsNaN(v)
+}
+
+func isValidTemperature(v float64) bool {
+	return v >= minTemperatureC && v <= maxTemperatureC && !math.IsNaN(v)
+}
@@ -0,0 +1,138 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForVitalSigns(t *testing.T) {
+	tests := []struct {
+		desc           string
+		clinicalNote   *model.ClinicalNote
+		expectedMetric *model.HealthMetric
+		expectedReview int
+	}{
+		{
+			desc:           "no vital signs",
+			clinicalNote:   &model.ClinicalNote{Text: "lorem ipsum dolor sit amet"},
+			expectedMetric: &model.HealthMetric{},
+		},
+		{
+			desc:         "full set of observations",
+			clinicalNote: &model.ClinicalNote{Text: "Obs: HR 72 bpm, RR 16, SpO2 97% on RA, temp 37.2°C"},
+			expectedMetric: &model.HealthMetric{
+				HeartRate:        qty(72, "/min"),
+				RespiratoryRate:  qty(16, "/min"),
+				OxygenSaturation: qty(97, "%"),
+				OxygenSupport:    model.OxygenRoomAir,
+				Temperature:      qty(37.2, "Cel"),
+			},
+		},
+		{
+			desc:         "pulse and resp rate written out",
+			clinicalNote: &model.ClinicalNote{Text: "pulse of 110 beats per minute, respiratory rate 22 breaths/min"},
+			expectedMetric: &model.HealthMetric{
+				HeartRate:       qty(110, "/min"),
+				RespiratoryRate: qty(22, "/min"),
+			},
+		},
+		{
+			desc:         "saturation on supplemental oxygen",
+			clinicalNote: &model.ClinicalNote{Text: "sats 94% on 2L O2 via nasal cannula"},
+			expectedMetric: &model.HealthMetric{
+				OxygenSaturation: qty(94, "%"),
+				OxygenSupport:    model.OxygenSupplemental,
+				OxygenFlow:       qty(2, "L/min"),
+			},
+		},
+		{
+			desc:         "saturation on room air written out",
+			clinicalNote: &model.ClinicalNote{Text: "oxygen saturation 99% room air"},
+			expectedMetric: &model.HealthMetric{
+				OxygenSaturation: qty(99, "%"),
+				OxygenSupport:    model.OxygenRoomAir,
+			},
+		},
+		{
+			desc:           "saturation without oxygen context",
+			clinicalNote:   &model.ClinicalNote{Text: "SpO2: 96"},
+			expectedMetric: &model.HealthMetric{OxygenSaturation: qty(96, "%")},
+		},
+		{
+			desc:           "temperature in fahrenheit is converted",
+			clinicalNote:   &model.ClinicalNote{Text: "temperature 101.3 F"},
+			expectedMetric: &model.HealthMetric{Temperature: qty(38.5, "Cel")},
+		},
+		{
+			desc:           "temperature in degrees fahrenheit is converted",
+			clinicalNote:   &model.ClinicalNote{Text: "temp 98.6 degrees fahrenheit"},
+			expectedMetric: &model.HealthMetric{Temperature: qty(37, "Cel")},
+		},
+		{
+			desc:           "unitless temperature is read by magnitude",
+			clinicalNote:   &model.ClinicalNote{Text: "temp 99.5, temp 38.1"},
+			expectedMetric: &model.HealthMetric{Temperature: qty(37.5, "Cel")},
+		},
+		{
+			desc:           "saturation on air",
+			clinicalNote:   &model.ClinicalNote{Text: "SpO2 98% on air"},
+			expectedMetric: &model.HealthMetric{OxygenSaturation: qty(98, "%"), OxygenSupport: model.OxygenRoomAir},
+		},
+		{
+			desc:           "saturation without oxygen is not negated",
+			clinicalNote:   &model.ClinicalNote{Text: "SpO2 95% without O2"},
+			expectedMetric: &model.HealthMetric{OxygenSaturation: qty(95, "%")},
+		},
+		{
+			desc:           "hours of a duration are not a heart rate",
+			clinicalNote:   &model.ClinicalNote{Text: "nil by mouth for 6 hr 10 min, after 24 hr 300 ml urine"},
+			expectedMetric: &model.HealthMetric{},
+		},
+		{
+			desc:           "implausible heart rate held back for review",
+			clinicalNote:   &model.ClinicalNote{Text: "HR 350"},
+			expectedMetric: &model.HealthMetric{},
+			expectedReview: 1,
+		},
+		{
+			desc:           "implausible oxygen saturation held back for review",
+			clinicalNote:   &model.ClinicalNote{Text: "sats 45% on 15L"},
+			expectedMetric: &model.HealthMetric{},
+			expectedReview: 1,
+		},
+		{
+			desc:           "implausible temperature held back for review",
+			clinicalNote:   &model.ClinicalNote{Text: "temp 50 C"},
+			expectedMetric: &model.HealthMetric{},
+			expectedReview: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMetric, vitalSigns(healthMetric))
+			assert.Len(t, healthMetric.NeedsReview, tt.expectedReview)
+		})
+	}
+}
+
+// vitalSigns keeps only the vital sign fields so the table can ignore everything else in the response.
+func vitalSigns(m *model.HealthMetric) *model.HealthMetric {
+	return &model.HealthMetric{
+		HeartRate:        m.HeartRate,
+		RespiratoryRate:  m.RespiratoryRate,
+		OxygenSaturation: m.OxygenSaturation,
+		OxygenSupport:    m.OxygenSupport,
+		OxygenFlow:       m.OxygenFlow,
+		Temperature:      m.Temperature,
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+)
+
+// weightChangeAmount is the magnitude of a change, either a mass or a percentage of body weight.
+const weightChangeAmount = `(?P<value>\d{1,3}(?:\.\d{1,2})?)\s*(?P<unit>%|(?:kilograms?|kgs?|pounds?|lbs?|stones?|st)\b)`
+
+var (
+	// weightChangeRegexes match the ways a loss or gain is written: "lost 5 kg", "weight down 4kg",
+	// "weight loss of 5%" and "5% weight loss". Each captures the direction, value and unit by name.
+	weightChangeRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?P<direction>lost|losing|gained|gaining|dropped|put\s+on)\s+` + weightChangeAmount + `(?:\s+(?:of\s+)?(?:(?:body\s*)?weight|wt)\b)?`),
+		regexp.MustCompile(`(?i)\b(?:weight|wt)\s+(?:has\s+|had\s+)?(?:gone\s+)?(?P<direction>down|up|decreased|increased|dropped|fallen|fell|risen|rose|reduced|loss|gain)\s*(?:of|by|:)?\s*` + weightChangeAmount),
+		regexp.MustCompile(`(?i)\b` + weightChangeAmount + `\s+(?:of\s+)?(?:(?:body\s*)?weight|wt)\s+(?P<direction>loss|gain)\b`),
+	}
+	// weightChangePeriodRegex reads the time frame straight after a change: "in 3 months", "over the last 6
+	// weeks" or "since June".
+	weightChangePeriodRegex = regexp.MustCompile(`(?i)^\s*(?:(?:in|over|during|within)\s+(?:the\s+)?(?:last\s+|past\s+)?(?P<duration>\d{1,3}\s*(?:days?|weeks?|wks?|months?|mths?|years?|yrs?))\b|(?P<since>since\s+[\w/]+))`)
+)
+
+// weightChangeDirections maps the words that introduce a change to its direction.
+var weightChangeDirections = map[string]string{
+	"lost": model.WeightLoss, "losing": model.WeightLoss, "dropped": model.WeightLoss, "down": model.WeightLoss,
+	"decreased": model.WeightLoss, "fallen": model.WeightLoss, "fell": model.WeightLoss, "reduced": model.WeightLoss,
+	"loss": model.WeightLoss,
+
+	"gained": model.WeightGain, "gaining": model.WeightGain, "puton": model.WeightGain, "up": model.WeightGain,
+	"increased": model.WeightGain, "risen": model.WeightGain, "rose": model.WeightGain, "gain": model.WeightGain,
+}
+
+// apply sensible ranges for a change in weight
+const (
+	maxWeightChangeKg      = maxWeightKg
+	maxWeightChangePercent = 100.0
+)
+
+// extractWeightChangeMetrics returns every loss or gain in weight in the note, in order of appearance. The
+// magnitude is normalized to kg unless it is a percentage of body weight. A change of nothing or of more than
+// is plausible is marked implausible rather than failing the note.
+func extractWeightChangeMetrics(text string) ([]model.Observation, error) {
+	var observations []model.Observation
+	for _, r := range weightChangeRegexes {
+		for _, m := range r.FindAllStringSubmatchIndex(text, -1) {
+			observation, err := weightChangeObservation(text, r, m)
+			if err != nil {
+				return nil, err
+			}
+			observations = append(observations, observation)
+		}
+	}
+	sort.SliceStable(observations, func(i, j int) bool { return observations[i].Start < observations[j].Start })
+
+	return observations, nil
+}
+
+func weightChangeObservation(text string, r *regexp.Regexp, m []int) (model.Observation, error) {
+	group := func(name string) string {
+		i := r.SubexpIndex(name)
+		return text[m[2*i]:m[2*i+1]]
+	}
+	valStr, unitStr := group("value"), group("unit")
+	v, err := strconv.ParseFloat(valStr, 64)
+	if err != nil {
+		return model.Observation{}, fmt.Errorf("unable to parse given weight change of %s: %w", text[m[0]:m[1]], err)
+	}
+
+	end := m[1]
+	var period string
+	if p := weightChangePeriodRegex.FindStringSubmatchIndex(text[end:]); p != nil {
+		if p[2] >= 0 {
+			period = text[end+p[2] : end+p[3]]
+		} else {
+			period = text[end+p[4] : end+p[5]]
+		}
+		end += p[1]
+	}
+
+	var observation model.Observation
+	if unitStr == "%" {
+		observation = newObservation(text, m[0], end, model.KindWeightChange, quantity.Quantity{Value: v, Unit: "%"})
+		observation.Implausible = v <= 0 || v > maxWeightChangePercent
+	} else {
+		q, err := parseQuantity(valStr, unitStr)
+		if err != nil {
+			return model.Observation{}, fmt.Errorf("unable to parse given weight change of %s: %w", text[m[0]:m[1]], err)
+		}
+		kg, err := q.To("kg")
+		if err != nil {
+			return model.Observation{}, err
+		}
+		observation = newObservation(text, m[0], end, model.KindWeightChange, kg.Round(outputPrecision[model.KindWeightChange]))
+		observation.Written = &q
+		observation.Implausible = kg.Value <= 0 || kg.Value > maxWeightChangeKg
+	}
+	observation.Direction = weightChangeDirections[strings.ToLower(strings.Join(strings.Fields(group("direction")), ""))]
+	observation.Period = period
+
+	return observation, nil
+}
+
+// weightChangeIn reports a weight change in the unit system of the response; a percentage is unitless and is
+// reported as written.
+func weightChangeIn(o *model.Observation, system string) (*model.WeightChange, error) {
+	if o == nil {
+		return nil, nil
+	}
+	change := &model.WeightChange{Direction: o.Direction, Change: o.Quantity(), Period: o.Period}
+	if o.Unit == "%" {
+		return change, nil
+	}
+	q, err := inUnitSystem(o, system)
+	if err != nil {
+		return nil, err
+	}
+	change.Change = *q
+
+	return change, nil
+}
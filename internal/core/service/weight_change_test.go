@@ -0,0 +1,96 @@
+package service_test
+
+import (
+	"testing"
+
+	"cleo.com/internal/core/domain/model"
+	"cleo.com/internal/core/domain/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserService_ParseClinicalNote_ForWeightChange(t *testing.T) {
+	tests := []struct {
+		desc                 string
+		clinicalNote         *model.ClinicalNote
+		expectedWeightChange *model.WeightChange
+		expectedWeight       *quantity.Quantity
+	}{
+		{
+			desc:         "loss with a period",
+			clinicalNote: &model.ClinicalNote{Text: "lost 5 kg in 3 months"},
+			expectedWeightChange: &model.WeightChange{
+				Direction: model.WeightLoss,
+				Change:    quantity.Quantity{Value: 5, Unit: "kg"},
+				Period:    "3 months",
+			},
+		},
+		{
+			desc:         "weight down since a month",
+			clinicalNote: &model.ClinicalNote{Text: "weight down 4kg since June"},
+			expectedWeightChange: &model.WeightChange{
+				Direction: model.WeightLoss,
+				Change:    quantity.Quantity{Value: 4, Unit: "kg"},
+				Period:    "since June",
+			},
+		},
+		{
+			desc:         "gain in pounds is converted",
+			clinicalNote: &model.ClinicalNote{Text: "gained 10 lbs over 6 weeks"},
+			expectedWeightChange: &model.WeightChange{
+				Direction: model.WeightGain,
+				Change:    quantity.Quantity{Value: 4.54, Unit: "kg"},
+				Period:    "6 weeks",
+			},
+		},
+		{
+			desc:         "percentage loss",
+			clinicalNote: &model.ClinicalNote{Text: "5% weight loss"},
+			expectedWeightChange: &model.WeightChange{
+				Direction: model.WeightLoss,
+				Change:    quantity.Quantity{Value: 5, Unit: "%"},
+			},
+		},
+		{
+			desc:         "weight loss of an amount",
+			clinicalNote: &model.ClinicalNote{Text: "unintentional weight loss of 6.5kg over the last 6 months"},
+			expectedWeightChange: &model.WeightChange{
+				Direction: model.WeightLoss,
+				Change:    quantity.Quantity{Value: 6.5, Unit: "kg"},
+				Period:    "6 months",
+			},
+		},
+		{
+			desc:         "change alongside the current weight",
+			clinicalNote: &model.ClinicalNote{Text: "weight 72kg, has lost 8 kg since Christmas"},
+			expectedWeightChange: &model.WeightChange{
+				Direction: model.WeightLoss,
+				Change:    quantity.Quantity{Value: 8, Unit: "kg"},
+				Period:    "since Christmas",
+			},
+			expectedWeight: qty(72, "kg"),
+		},
+		{
+			desc:         "percentage above one hundred held back for review",
+			clinicalNote: &model.ClinicalNote{Text: "lost 120% of body weight"},
+		},
+		{
+			desc:           "change of nothing does not hide the weight",
+			clinicalNote:   &model.ClinicalNote{Text: "lost 0 kg, weight 80kg"},
+			expectedWeight: qty(80, "kg"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			testService := newTestParserService(t)
+			healthMetric, err := testService.ParseClinicalNote(tt.clinicalNote)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedWeightChange, healthMetric.WeightChange)
+			assert.Equal(t, tt.expectedWeight, healthMetric.Weight)
+		})
+	}
+}